@@ -0,0 +1,109 @@
+package gocognit
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GroupStat is the aggregated complexity of a group of functions, either
+// the method set of a type or a package.
+type GroupStat struct {
+	PkgName       string
+	Dir           string // directory of the package
+	TypeName      string `json:",omitempty"` // empty for a package
+	Funcs         int    // number of functions or methods
+	Complexity    int    // total complexity
+	MaxComplexity int
+	Average       float64
+}
+
+func (s GroupStat) String() string {
+	name := s.TypeName
+	if name == "" {
+		name = s.Dir
+	}
+
+	return fmt.Sprintf("%d %s %s funcs=%d max=%d avg=%.3g", s.Complexity, s.PkgName, name, s.Funcs, s.MaxComplexity, s.Average)
+}
+
+// TypeStats aggregates the method statistics by their receiver type. The
+// methods with value and pointer receivers belong to the same type, the
+// functions without receiver are left out.
+//
+// The result is sorted by the total complexity, the highest first.
+func TypeStats(stats []Stat) []GroupStat {
+	return groupStats(stats, func(stat Stat) (string, bool) {
+		return receiverType(stat.FuncName)
+	})
+}
+
+// PackageStats aggregates the function statistics by their package, the
+// packages with the same name in different directories are different
+// packages.
+//
+// The result is sorted by the total complexity, the highest first.
+func PackageStats(stats []Stat) []GroupStat {
+	return groupStats(stats, func(stat Stat) (string, bool) {
+		return "", true
+	})
+}
+
+func groupStats(stats []Stat, typeName func(Stat) (string, bool)) []GroupStat {
+	index := make(map[string]int)
+
+	var groups []GroupStat
+	for _, stat := range stats {
+		name, ok := typeName(stat)
+		if !ok {
+			continue
+		}
+
+		dir := filepath.Dir(stat.Pos.Filename)
+		key := dir + "\x00" + stat.PkgName + "\x00" + name
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, GroupStat{
+				PkgName:  stat.PkgName,
+				Dir:      dir,
+				TypeName: name,
+			})
+		}
+
+		g := &groups[i]
+		g.Funcs++
+		g.Complexity += stat.Complexity
+		if stat.Complexity > g.MaxComplexity {
+			g.MaxComplexity = stat.Complexity
+		}
+	}
+
+	for i := range groups {
+		groups[i].Average = float64(groups[i].Complexity) / float64(groups[i].Funcs)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Complexity > groups[j].Complexity
+	})
+
+	return groups
+}
+
+// receiverType returns the receiver type name of the method name in the
+// form of "(T).Name" or "(*T).Name", the type parameters are already left
+// out by recvString.
+func receiverType(funcName string) (string, bool) {
+	if !strings.HasPrefix(funcName, "(") {
+		return "", false
+	}
+
+	end := strings.Index(funcName, ").")
+	if end < 0 {
+		return "", false
+	}
+
+	return strings.TrimPrefix(funcName[1:end], "*"), true
+}
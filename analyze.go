@@ -0,0 +1,410 @@
+package gocognit
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Options is the options to analyze the Go source files.
+type Options struct {
+	ScanOptions
+
+	IncludeTests bool           // include the _test.go files
+	Ignore       *regexp.Regexp // skip the files which path matches it
+
+	// Exclude is the glob patterns, as understood by filepath.Match, of the
+	// files and directories to skip. A pattern is matched against the base
+	// name and the path relative to the walked directory, with forward
+	// slashes. AnalyzePackages matches the paths relative to the working
+	// directory instead.
+	Exclude []string
+
+	// Strict stops the analysis at the first file error, otherwise the
+	// errors are collected and the other functions are still analyzed.
+	Strict bool
+
+	// Concurrency is the number of files analyzed at the same time, 0
+	// means runtime.GOMAXPROCS(0).
+	Concurrency int
+}
+
+func (o Options) ignored(filename string) bool {
+	return o.Ignore != nil && o.Ignore.MatchString(filename)
+}
+
+func (o Options) excluded(path string) bool {
+	base, slashed := filepath.Base(path), filepath.ToSlash(path)
+	for _, pattern := range o.Exclude {
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+
+		if ok, _ := filepath.Match(pattern, slashed); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// excludedFile reports whether the file or any of its directories is
+// excluded, the path is made relative to the working directory first.
+func (o Options) excludedFile(filename string) bool {
+	if len(o.Exclude) == 0 {
+		return false
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			filename = rel
+		}
+	}
+
+	for path := filename; ; {
+		if o.excluded(path) {
+			return true
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path || parent == "." {
+			return false
+		}
+
+		path = parent
+	}
+}
+
+func (o Options) checkExclude() error {
+	for _, pattern := range o.Exclude {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("exclude pattern %q: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// skippedDir reports whether the directory is skipped by the go tool, the
+// vendor and testdata directories and the ones which name starts with . or
+// _ are skipped, so are the node_modules directories.
+func skippedDir(name string) bool {
+	switch name {
+	case "vendor", "testdata", "node_modules":
+		return true
+	}
+
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// AnalyzeFile analyzes the Go source file.
+func AnalyzeFile(ctx context.Context, filename string, opts Options) ([]Stat, error) {
+	return AnalyzePaths(ctx, []string{filename}, opts)
+}
+
+// AnalyzeDir analyzes the Go source files in the directory and its sub
+// directories.
+func AnalyzeDir(ctx context.Context, dir string, opts Options) ([]Stat, error) {
+	return AnalyzePaths(ctx, []string{dir}, opts)
+}
+
+// AnalyzePaths analyzes the Go source files and the directories. The
+// files given explicitly are analyzed even when they are tests.
+//
+// The sub directories skipped by the go tool, such as vendor and testdata,
+// are not walked, they can still be given as the paths explicitly.
+//
+// The stats are in the order of the paths, the files of a directory are
+// in lexical order. Unless the Strict option is set, the files which can
+// not be read or parsed do not stop the analysis, the stats of the parsed
+// functions are returned together with the Errors of the files.
+func AnalyzePaths(ctx context.Context, paths []string, opts Options) ([]Stat, error) {
+	if err := opts.checkExclude(); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !fi.IsDir() {
+			if !opts.ignored(path) && !opts.excluded(path) {
+				files = append(files, path)
+			}
+
+			continue
+		}
+
+		found, err := sourceFiles(path, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, found...)
+	}
+
+	return analyzeFiles(ctx, files, opts)
+}
+
+func sourceFiles(dir string, opts Options) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path != dir && (skippedDir(info.Name()) || opts.excluded(rel)) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		if !opts.IncludeTests && strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		if opts.ignored(path) || opts.excluded(rel) {
+			return nil
+		}
+
+		files = append(files, path)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func analyzeFiles(ctx context.Context, files []string, opts Options) ([]Stat, error) {
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	results := make([][]Stat, len(files))
+	errs := make([]error, len(files))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				results[i], errs[i] = analyzeFile(files[i], nil, opts.ScanOptions)
+			}
+		}()
+	}
+
+feed:
+	for i := range files {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		stats    []Stat
+		fileErrs Errors
+	)
+	for i := range files {
+		if errs[i] != nil {
+			if opts.Strict {
+				return nil, errs[i]
+			}
+
+			fileErrs = append(fileErrs, errs[i].(Errors)...)
+		}
+
+		stats = append(stats, results[i]...)
+	}
+
+	return stats, fileErrs.Err()
+}
+
+// AnalyzeSource analyzes the Go source, such as the unsaved content of an
+// editor buffer. The filename is only used for the positions.
+//
+// When the source has syntax errors, the stats of the functions parsed
+// successfully are returned together with the Errors.
+func AnalyzeSource(filename string, src []byte, opts ScanOptions) ([]Stat, error) {
+	return analyzeFile(filename, src, opts)
+}
+
+// AnalyzeReader analyzes the Go source read from r, such as the standard
+// input. The filename is only used for the positions.
+//
+// When the source has syntax errors, the stats of the functions parsed
+// successfully are returned together with the Errors.
+func AnalyzeReader(filename string, r io.Reader, opts ScanOptions) ([]Stat, error) {
+	return analyzeFile(filename, r, opts)
+}
+
+// analyzeFile analyzes the source, or reads it from the file when src is
+// nil. The error is always Errors.
+func analyzeFile(filename string, src interface{}, opts ScanOptions) ([]Stat, error) {
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.AllErrors)
+	if err == nil {
+		return ComplexityStatsWithOptions(f, fset, nil, opts), nil
+	}
+
+	list, ok := err.(scanner.ErrorList)
+	if !ok || f == nil {
+		return nil, Errors{{Pos: token.Position{Filename: filename}, Msg: err.Error()}}
+	}
+
+	// one error per line is enough
+	list.RemoveMultiples()
+
+	var errs Errors
+	for _, e := range list {
+		errs = append(errs, FileError{Pos: e.Pos, Msg: e.Msg})
+	}
+
+	f.Decls = withoutBrokenFuncs(fset, f.Decls, errs)
+
+	return ComplexityStatsWithOptions(f, fset, nil, opts), errs
+}
+
+// withoutBrokenFuncs leaves out the functions having errors, including the
+// ones which body is not closed.
+func withoutBrokenFuncs(fset *token.FileSet, decls []ast.Decl, errs Errors) []ast.Decl {
+	var out []ast.Decl
+	for _, decl := range decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && isBrokenFunc(fset, fn, errs) {
+			continue
+		}
+
+		out = append(out, decl)
+	}
+
+	return out
+}
+
+func isBrokenFunc(fset *token.FileSet, fn *ast.FuncDecl, errs Errors) bool {
+	if fn.Body != nil && !fn.Body.Rbrace.IsValid() {
+		return true
+	}
+
+	pos, end := fset.Position(fn.Pos()), fset.Position(fn.End())
+	for _, e := range errs {
+		if e.Pos.Offset >= pos.Offset && e.Pos.Offset < end.Offset {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AnalyzePackages loads the packages matching the patterns, as understood
+// by the go command, and analyzes their Go source files. The type
+// information of the packages is used to recognize the error values of the
+// Go idiom mode.
+//
+// The packages are loaded and parsed by golang.org/x/tools/go/packages,
+// the Concurrency option is not used. The Ignore and Exclude options skip
+// the files of the packages. The type errors are tolerated, the other
+// errors are returned as Errors unless the Strict option is set.
+func AnalyzePackages(ctx context.Context, patterns []string, opts Options) ([]Stat, error) {
+	if err := opts.checkExclude(); err != nil {
+		return nil, err
+	}
+
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedTypesInfo,
+		Tests: opts.IncludeTests,
+	}
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	// with tests, the files of a package are also part of its test variant
+	seen := make(map[string]bool)
+
+	var (
+		stats []Stat
+		errs  Errors
+	)
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			// the generated test main package
+			continue
+		}
+
+		for _, err := range pkg.Errors {
+			if err.Kind == packages.TypeError {
+				continue
+			}
+
+			if opts.Strict {
+				return nil, err
+			}
+
+			errs = append(errs, FileError{Msg: err.Error()})
+		}
+
+		scanOpts := opts.ScanOptions
+		scanOpts.TypesInfo = pkg.TypesInfo
+
+		for _, f := range pkg.Syntax {
+			filename := pkg.Fset.Position(f.Pos()).Filename
+			if seen[filename] || opts.ignored(filename) || opts.excludedFile(filename) {
+				continue
+			}
+
+			seen[filename] = true
+			stats = ComplexityStatsWithOptions(f, pkg.Fset, stats, scanOpts)
+		}
+	}
+
+	return stats, errs.Err()
+}
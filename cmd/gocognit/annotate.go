@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/uudashr/gocognit"
+)
+
+// sources are the Go sources which were analyzed from memory, such as the
+// standard input, by the file name they are reported with. The other files
+// are read from the disk.
+type sources map[string][]byte
+
+func (s sources) read(filename string) ([]byte, error) {
+	if src, ok := s[filename]; ok {
+		return src, nil
+	}
+
+	return os.ReadFile(filename)
+}
+
+// writeAnnotatedStats writes the source of each function with the
+// complexity increments rendered as trailing comments.
+func writeAnnotatedStats(w io.Writer, stats []gocognit.Stat, src sources) (int, error) {
+	for i, stat := range stats {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		if err := writeAnnotatedFunc(w, stat, src); err != nil {
+			return i, err
+		}
+	}
+
+	return len(stats), nil
+}
+
+func writeAnnotatedFunc(w io.Writer, stat gocognit.Stat, src sources) error {
+	lines, err := funcSourceLines(stat, src)
+	if err != nil {
+		return err
+	}
+
+	comments := annotationComments(stat)
+
+	width := 0
+	for i, line := range lines {
+		if _, ok := comments[stat.Pos.Line+i]; ok && len(line) > width {
+			width = len(line)
+		}
+	}
+
+	fmt.Fprintf(w, "// %s %s %s\n", stat.Pos, stat.PkgName, stat.FuncName)
+
+	last := len(lines) - 1
+	for i, line := range lines {
+		if comment, ok := comments[stat.Pos.Line+i]; ok {
+			fmt.Fprintf(w, "%-*s // %s\n", width, line, comment)
+			continue
+		}
+
+		if i == last {
+			fmt.Fprintf(w, "%s // total complexity = %d\n", line, stat.Complexity)
+			continue
+		}
+
+		fmt.Fprintln(w, line)
+	}
+
+	return nil
+}
+
+// annotationComments returns the comment text of the diagnostics grouped
+// by line, each one ending with the running total of the complexity.
+func annotationComments(stat gocognit.Stat) map[int]string {
+	incs := make(map[int][]string)
+	totals := make(map[int]int)
+
+	total := 0
+	for _, diag := range stat.Diagnostics {
+		total += diag.Inc
+
+		line := diag.Pos.Line
+		incs[line] = append(incs[line], fmt.Sprintf("%s %s", diag, diag.Text))
+		totals[line] = total
+	}
+
+	comments := make(map[int]string, len(incs))
+	for line, texts := range incs {
+		comments[line] = fmt.Sprintf("%s (total %d)", strings.Join(texts, ", "), totals[line])
+	}
+
+	return comments
+}
+
+// funcSourceLines returns the source lines of the function, with the
+// leading tabs expanded so the annotations line up.
+func funcSourceLines(stat gocognit.Stat, srcs sources) ([]string, error) {
+	src, err := srcs.read(stat.Pos.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if stat.End.Offset > len(src) || stat.Pos.Offset > stat.End.Offset {
+		return nil, fmt.Errorf("%s: source of %s has changed", stat.Pos, stat.FuncName)
+	}
+
+	start := bytes.LastIndexByte(src[:stat.Pos.Offset], '\n') + 1
+	body := string(src[start:stat.End.Offset])
+
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		lines[i] = expandIndent(line)
+	}
+
+	return lines, nil
+}
+
+func expandIndent(line string) string {
+	trimmed := strings.TrimLeft(line, "\t")
+	return strings.Repeat("    ", len(line)-len(trimmed)) + trimmed
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/uudashr/gocognit"
+)
+
+const annotateSrc = `package p
+
+func Sum(ss []string) (total int) {
+	for _, s := range ss {
+		if s == "" || s == "-" {
+			continue
+		}
+		total++
+	}
+	return total
+}
+
+func Empty() {}
+`
+
+func analyzeTestSource(t *testing.T, filename, src string) []gocognit.Stat {
+	t.Helper()
+
+	stats, err := gocognit.AnalyzeSource(filename, []byte(src), gocognit.ScanOptions{Diagnostics: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return stats
+}
+
+func TestWriteAnnotatedStats(t *testing.T) {
+	stats := analyzeTestSource(t, "p.go", annotateSrc)
+
+	var buf bytes.Buffer
+	n, err := writeAnnotatedStats(&buf, stats, sources{"p.go": []byte(annotateSrc)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 2 {
+		t.Errorf("got %d functions, want 2", n)
+	}
+
+	want := `// p.go:3:1 p Sum
+func Sum(ss []string) (total int) {
+    for _, s := range ss {       // +1 for (total 1)
+        if s == "" || s == "-" { // +2 (nesting=1) if, +1 || (total 4)
+            continue
+        }
+        total++
+    }
+    return total
+} // total complexity = 4
+
+// p.go:13:1 p Empty
+func Empty() {} // total complexity = 0
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteAnnotatedStats_SourceChanged(t *testing.T) {
+	stats := analyzeTestSource(t, "p.go", annotateSrc)
+
+	var buf bytes.Buffer
+	_, err := writeAnnotatedStats(&buf, stats, sources{"p.go": []byte("package p\n")})
+	if err == nil {
+		t.Fatal("got no error, want the source has changed")
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/uudashr/gocognit"
+)
+
+// readBaseline reads the stats of a previous run, either the array of the
+// -json flag or the document of -format json-report.
+func readBaseline(filename string) ([]gocognit.Stat, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if b = bytes.TrimSpace(b); len(b) > 0 && b[0] == '[' {
+		var stats []gocognit.Stat
+		if err := json.Unmarshal(b, &stats); err != nil {
+			return nil, err
+		}
+
+		return stats, nil
+	}
+
+	var out jsonReport
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+
+	return out.Stats, nil
+}
+
+// statKey identifies a function across runs, the position is left out so
+// the function can still be matched when the code around it moves.
+func statKey(stat gocognit.Stat) string {
+	return stat.Pos.Filename + "\x00" + stat.PkgName + "\x00" + stat.FuncName
+}
+
+// pkgKey identifies a package, packages with the same name in different
+// directories are different packages.
+func pkgKey(stat gocognit.Stat) string {
+	return filepath.Dir(stat.Pos.Filename) + "\x00" + stat.PkgName
+}
+
+func baselineComplexities(stats []gocognit.Stat) map[string]int {
+	m := make(map[string]int, len(stats))
+	for _, stat := range stats {
+		m[statKey(stat)] += stat.Complexity
+	}
+
+	return m
+}
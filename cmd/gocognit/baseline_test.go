@@ -0,0 +1,74 @@
+package main
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/uudashr/gocognit"
+)
+
+func TestReadBaseline(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "old.json")
+	data := `[{"PkgName":"p","FuncName":"Sum","Complexity":3,"Pos":{"Filename":"p/p.go","Line":3,"Column":1}}]`
+	if err := os.WriteFile(filename, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := readBaseline(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 1 || stats[0].FuncName != "Sum" || stats[0].Complexity != 3 || stats[0].Pos.Filename != "p/p.go" {
+		t.Errorf("got %+v, want Sum with complexity 3 in p/p.go", stats)
+	}
+}
+
+func TestReadBaseline_Report(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "old.json")
+	data := `{"Stats":[{"PkgName":"p","FuncName":"Sum","Complexity":3}],"Errors":[]}`
+	if err := os.WriteFile(filename, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := readBaseline(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 1 || stats[0].FuncName != "Sum" || stats[0].Complexity != 3 {
+		t.Errorf("got %+v, want Sum with complexity 3", stats)
+	}
+}
+
+func TestReadBaseline_Invalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "old.json")
+	if err := os.WriteFile(filename, []byte("p/p.go:3:1 p Sum 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readBaseline(filename); err == nil {
+		t.Error("got no error, want the invalid JSON error")
+	}
+}
+
+func TestBaselineComplexities(t *testing.T) {
+	stats := []gocognit.Stat{
+		{PkgName: "p", FuncName: "Sum", Complexity: 3, Pos: token.Position{Filename: "p/p.go", Line: 3}},
+		// the functions of the same name in a file are summed
+		{PkgName: "p", FuncName: "Sum", Complexity: 3, Pos: token.Position{Filename: "p/p.go", Line: 30}},
+		{PkgName: "q", FuncName: "Sum", Complexity: 1, Pos: token.Position{Filename: "p/p.go", Line: 3}},
+	}
+
+	got := baselineComplexities(stats)
+
+	if n := got[statKey(stats[1])]; n != 6 {
+		t.Errorf("got complexity %d for p.Sum, want 6", n)
+	}
+
+	if n := got[statKey(stats[2])]; n != 1 {
+		t.Errorf("got complexity %d for q.Sum, want 1", n)
+	}
+}
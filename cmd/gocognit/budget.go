@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/uudashr/gocognit"
+)
+
+const (
+	packageBudget = "package"
+	fileBudget    = "file"
+	dirBudget     = "dir"
+)
+
+// budgetContributors is the number of the most complex functions reported
+// for an exceeded budget, the ones with no complexity are left out.
+const budgetContributors = 5
+
+// budget is the cap of the total complexity of each group of a scope, the
+// paths override the default for the package directories, files or
+// directories given by their slashed path. 0 disables it.
+type budget struct {
+	Default int            `json:"default"`
+	Paths   map[string]int `json:"paths,omitempty"`
+}
+
+func (b budget) enabled() bool {
+	return b.Default > 0 || len(b.Paths) > 0
+}
+
+// limit returns the budget of the group, 0 when it has none.
+func (b budget) limit(name string) int {
+	if n, ok := b.Paths[filepath.ToSlash(filepath.Clean(name))]; ok {
+		return n
+	}
+
+	return b.Default
+}
+
+// budgets are the budgets of the scopes.
+type budgets struct {
+	Package budget `json:"package"`
+	File    budget `json:"file"`
+	Dir     budget `json:"dir"`
+}
+
+func (b budgets) enabled() bool {
+	return b.Package.enabled() || b.File.enabled() || b.Dir.enabled()
+}
+
+// budgetResult is a group exceeding its budget with its most complex
+// functions.
+type budgetResult struct {
+	Scope        string // package, file or dir
+	Name         string // directory of the package, file or directory
+	PkgName      string `json:",omitempty"`
+	Complexity   int
+	Budget       int
+	Contributors []budgetContributor
+}
+
+func (r budgetResult) String() string {
+	name := r.Name
+	if r.Scope == packageBudget {
+		name = fmt.Sprintf("%s (%s)", r.Name, r.PkgName)
+	}
+
+	return fmt.Sprintf("%s %s complexity=%d budget=%d", r.Scope, name, r.Complexity, r.Budget)
+}
+
+type budgetContributor struct {
+	PkgName    string
+	FuncName   string
+	Complexity int
+	Pos        token.Position
+}
+
+func (c budgetContributor) String() string {
+	return fmt.Sprintf("%d %s %s %s", c.Complexity, c.PkgName, c.FuncName, c.Pos)
+}
+
+// budgetKey identifies a group of a scope.
+type budgetKey struct {
+	name    string
+	pkgName string
+}
+
+// budgetGroup is the functions of a group in the order of the stats.
+type budgetGroup struct {
+	budgetKey
+	stats []gocognit.Stat
+}
+
+// checkBudgets returns the groups exceeding their budgets, by scope then
+// by complexity. The directories contain the functions of their sub
+// directories.
+func checkBudgets(stats []gocognit.Stat, b budgets) []budgetResult {
+	var results []budgetResult
+	for _, scope := range []struct {
+		name   string
+		budget budget
+		keys   func(gocognit.Stat) []budgetKey
+	}{
+		{packageBudget, b.Package, func(s gocognit.Stat) []budgetKey {
+			return []budgetKey{{name: filepath.Dir(s.Pos.Filename), pkgName: s.PkgName}}
+		}},
+		{fileBudget, b.File, func(s gocognit.Stat) []budgetKey {
+			return []budgetKey{{name: s.Pos.Filename}}
+		}},
+		{dirBudget, b.Dir, func(s gocognit.Stat) []budgetKey {
+			var keys []budgetKey
+			for _, dir := range parentDirs(s.Pos.Filename) {
+				keys = append(keys, budgetKey{name: dir})
+			}
+
+			return keys
+		}},
+	} {
+		if !scope.budget.enabled() {
+			continue
+		}
+
+		var found []budgetResult
+		for _, g := range groupByBudget(stats, scope.keys) {
+			limit := scope.budget.limit(g.name)
+			if limit <= 0 {
+				continue
+			}
+
+			total := 0
+			for _, stat := range g.stats {
+				total += stat.Complexity
+			}
+
+			if total > limit {
+				found = append(found, budgetResult{
+					Scope:        scope.name,
+					Name:         g.name,
+					PkgName:      g.pkgName,
+					Complexity:   total,
+					Budget:       limit,
+					Contributors: contributors(g.stats),
+				})
+			}
+		}
+
+		sort.SliceStable(found, func(i, j int) bool {
+			return found[i].Complexity > found[j].Complexity
+		})
+
+		results = append(results, found...)
+	}
+
+	return results
+}
+
+func groupByBudget(stats []gocognit.Stat, keys func(gocognit.Stat) []budgetKey) []*budgetGroup {
+	var (
+		groups []*budgetGroup
+		index  = make(map[budgetKey]*budgetGroup)
+	)
+
+	for _, stat := range stats {
+		for _, key := range keys(stat) {
+			g, ok := index[key]
+			if !ok {
+				g = &budgetGroup{budgetKey: key}
+				index[key] = g
+				groups = append(groups, g)
+			}
+
+			g.stats = append(g.stats, stat)
+		}
+	}
+
+	return groups
+}
+
+// parentDirs returns the directory of the file and its parents, up to the
+// root of the path.
+func parentDirs(filename string) []string {
+	var dirs []string
+	for dir := filepath.Dir(filename); ; {
+		dirs = append(dirs, dir)
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dirs
+		}
+
+		dir = parent
+	}
+}
+
+func contributors(stats []gocognit.Stat) []budgetContributor {
+	sorted := append([]gocognit.Stat(nil), stats...)
+	sort.Stable(byComplexity(sorted))
+
+	if len(sorted) > budgetContributors {
+		sorted = sorted[:budgetContributors]
+	}
+
+	out := make([]budgetContributor, 0, len(sorted))
+	for _, stat := range sorted {
+		if stat.Complexity == 0 {
+			break
+		}
+
+		out = append(out, budgetContributor{
+			PkgName:    stat.PkgName,
+			FuncName:   stat.FuncName,
+			Complexity: stat.Complexity,
+			Pos:        stat.Pos,
+		})
+	}
+
+	return out
+}
+
+// writeBudgetResults writes the functions exceeding their limits, then the
+// groups exceeding their budgets.
+func writeBudgetResults(w io.Writer, stats []gocognit.Stat, results []budgetResult, errs gocognit.Errors, tmpl *template.Template, jsonEncode bool) error {
+	if jsonEncode {
+		out := newJSONReport(stats, errs)
+		out.Budgets = results
+		return encodeJSONReport(w, out)
+	}
+
+	if _, err := writeTextStats(w, stats, tmpl); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		fmt.Fprintln(w, r)
+		for _, c := range r.Contributors {
+			fmt.Fprintf(w, "    %s\n", c)
+		}
+	}
+
+	return nil
+}
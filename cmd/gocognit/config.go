@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// config is the JSON configuration file given by -config flag:
+//
+//	{
+//	    "budgets": {
+//	        "package": {"default": 200, "paths": {"internal/legacy": 400}},
+//	        "file": {"default": 100},
+//	        "dir": {"paths": {"internal": 1000}}
+//	    }
+//	}
+type config struct {
+	Budgets budgets `json:"budgets"`
+}
+
+func readConfig(filename string) (config, error) {
+	var cfg config
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return cfg, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/uudashr/gocognit"
+)
+
+var csvHeader = []string{
+	"package",
+	"function",
+	"complexity",
+	"cyclomatic",
+	"file",
+	"line",
+	"column",
+	"end_line",
+	"increments",
+}
+
+// writeCSVStats writes the stats as delimiter separated values with a
+// header row. The increments column is left empty unless the diagnostics
+// are enabled.
+func writeCSVStats(w io.Writer, stats []gocognit.Stat, comma rune, includeDiagnostics bool) (int, error) {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	if err := cw.Write(csvHeader); err != nil {
+		return 0, err
+	}
+
+	for i, stat := range stats {
+		var increments string
+		if includeDiagnostics {
+			increments = strconv.Itoa(len(stat.Diagnostics))
+		}
+
+		record := []string{
+			stat.PkgName,
+			stat.FuncName,
+			strconv.Itoa(stat.Complexity),
+			strconv.Itoa(stat.Cyclomatic),
+			stat.Pos.Filename,
+			strconv.Itoa(stat.Pos.Line),
+			strconv.Itoa(stat.Pos.Column),
+			strconv.Itoa(stat.End.Line),
+			increments,
+		}
+		if err := cw.Write(record); err != nil {
+			return i, err
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return 0, err
+	}
+
+	return len(stats), nil
+}
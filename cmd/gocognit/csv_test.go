@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"testing"
+
+	"github.com/uudashr/gocognit"
+)
+
+var csvStats = []gocognit.Stat{
+	{
+		PkgName:    "p",
+		FuncName:   "(*List[K, V]).Push",
+		Complexity: 2,
+		Cyclomatic: 3,
+		Pos:        token.Position{Filename: "a,b/p.go", Line: 3, Column: 1},
+		End:        token.Position{Filename: "a,b/p.go", Line: 9, Column: 2},
+		Diagnostics: []gocognit.Diagnostic{
+			{Inc: 1, Text: "if"},
+			{Inc: 1, Text: "&&"},
+		},
+	},
+	{
+		PkgName:  "p",
+		FuncName: "Empty",
+		Pos:      token.Position{Filename: "a,b/p.go", Line: 11, Column: 1},
+		End:      token.Position{Filename: "a,b/p.go", Line: 11, Column: 16},
+	},
+}
+
+func TestWriteCSVStats(t *testing.T) {
+	tests := []struct {
+		name        string
+		comma       rune
+		diagnostics bool
+		want        string
+	}{
+		{
+			name:  "csv",
+			comma: ',',
+			want: `package,function,complexity,cyclomatic,file,line,column,end_line,increments
+p,"(*List[K, V]).Push",2,3,"a,b/p.go",3,1,9,
+p,Empty,0,0,"a,b/p.go",11,1,11,
+`,
+		},
+		{
+			name:        "csv with increments",
+			comma:       ',',
+			diagnostics: true,
+			want: `package,function,complexity,cyclomatic,file,line,column,end_line,increments
+p,"(*List[K, V]).Push",2,3,"a,b/p.go",3,1,9,2
+p,Empty,0,0,"a,b/p.go",11,1,11,0
+`,
+		},
+		{
+			name:  "tsv",
+			comma: '\t',
+			want: "package\tfunction\tcomplexity\tcyclomatic\tfile\tline\tcolumn\tend_line\tincrements\n" +
+				"p\t(*List[K, V]).Push\t2\t3\ta,b/p.go\t3\t1\t9\t\n" +
+				"p\tEmpty\t0\t0\ta,b/p.go\t11\t1\t11\t\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := writeCSVStats(&buf, csvStats, tt.comma, tt.diagnostics)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if n != len(csvStats) {
+				t.Errorf("got %d rows, want %d", n, len(csvStats))
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/uudashr/gocognit"
+)
+
+const (
+	diffChanged = "changed"
+	diffAdded   = "added"
+	diffRemoved = "removed"
+)
+
+// funcDiff is the change of the complexity of a function between two runs.
+type funcDiff struct {
+	PkgName  string
+	FuncName string
+	Pos      token.Position // the position in the new run, or in the old one when removed
+	Status   string         // changed, added or removed
+	Old      int
+	New      int
+	Delta    int
+}
+
+func (d funcDiff) String() string {
+	change := fmt.Sprintf("%d -> %d", d.Old, d.New)
+	if d.Status != diffChanged {
+		change = d.Status
+	}
+
+	return fmt.Sprintf("%s %s %s %s (%s)", formatDelta(d.Delta), d.PkgName, d.FuncName, d.Pos, change)
+}
+
+// runDiff runs the diff command, it compares the JSON output of a previous
+// run with another one or with the current stats of the paths.
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	var (
+		includeTests bool
+		jsonEncode   bool
+		ignoreExpr   string
+		excludes     stringsFlag
+		rules        = gocognit.DefaultRules()
+		goIdiom      bool
+	)
+
+	fs := newFlagSet(diffCommand, stderr)
+	fs.BoolVar(&includeTests, "test", true, "indicates whether test files should be included")
+	fs.BoolVar(&jsonEncode, "json", false, "encode the output as JSON")
+	fs.StringVar(&ignoreExpr, "ignore", "", "ignore files matching the given regexp")
+	fs.Var(&excludes, "exclude", "skip the files and directories matching the glob pattern")
+	fs.Var(&rules, "rules", "comma separated name=increment pairs overriding the scoring rules")
+	fs.BoolVar(&goIdiom, "go-idiom", false, "do not count the trivial error checks")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return 2
+	}
+
+	fail := func(err error) int {
+		fmt.Fprintf(stderr, "gocognit diff: %v\n", err)
+		return 1
+	}
+
+	oldStats, err := readBaseline(fs.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+
+	var newStats []gocognit.Stat
+	if paths := fs.Args()[1:]; len(paths) == 1 && strings.HasSuffix(paths[0], ".json") {
+		newStats, err = readBaseline(paths[0])
+	} else {
+		opts := gocognit.Options{
+			ScanOptions: gocognit.ScanOptions{
+				Rules:               &rules,
+				DiscountErrorChecks: goIdiom,
+			},
+			IncludeTests: includeTests,
+			Exclude:      excludes,
+		}
+
+		if opts.Ignore, err = prepareRegexp(ignoreExpr); err != nil {
+			return fail(err)
+		}
+
+		newStats, err = gocognit.AnalyzePaths(context.Background(), paths, opts)
+		if errs, ok := err.(gocognit.Errors); ok {
+			err = writeErrors(stderr, errs, jsonEncode)
+		}
+	}
+
+	if err != nil {
+		return fail(err)
+	}
+
+	diffs := diffStats(oldStats, newStats)
+
+	oldTotal, newTotal := 0, 0
+	for _, stat := range oldStats {
+		oldTotal += stat.Complexity
+	}
+
+	for _, stat := range newStats {
+		newTotal += stat.Complexity
+	}
+
+	if err := writeDiffs(stdout, diffs, oldTotal, newTotal, jsonEncode); err != nil {
+		return fail(err)
+	}
+
+	return 0
+}
+
+// diffStats returns the functions which complexity changed, the increases
+// first. The functions are matched by statKey, the ones with the same key
+// are summed up. The entries with a zero delta are dropped, including the
+// added and removed functions with no complexity.
+func diffStats(oldStats, newStats []gocognit.Stat) []funcDiff {
+	var (
+		diffs []funcDiff
+		index = make(map[string]int)
+	)
+
+	for _, stat := range newStats {
+		key := statKey(stat)
+		if i, ok := index[key]; ok {
+			diffs[i].New += stat.Complexity
+			continue
+		}
+
+		index[key] = len(diffs)
+		diffs = append(diffs, funcDiff{
+			PkgName:  stat.PkgName,
+			FuncName: stat.FuncName,
+			Pos:      stat.Pos,
+			Status:   diffAdded,
+			New:      stat.Complexity,
+		})
+	}
+
+	for _, stat := range oldStats {
+		key := statKey(stat)
+		if i, ok := index[key]; ok {
+			diffs[i].Old += stat.Complexity
+			if diffs[i].Status == diffAdded {
+				diffs[i].Status = diffChanged
+			}
+
+			continue
+		}
+
+		index[key] = len(diffs)
+		diffs = append(diffs, funcDiff{
+			PkgName:  stat.PkgName,
+			FuncName: stat.FuncName,
+			Pos:      stat.Pos,
+			Status:   diffRemoved,
+			Old:      stat.Complexity,
+		})
+	}
+
+	var changed []funcDiff
+	for _, d := range diffs {
+		d.Delta = d.New - d.Old
+		if d.Delta != 0 {
+			changed = append(changed, d)
+		}
+	}
+
+	sort.SliceStable(changed, func(i, j int) bool {
+		return changed[i].Delta > changed[j].Delta
+	})
+
+	return changed
+}
+
+func writeDiffs(w io.Writer, diffs []funcDiff, oldTotal, newTotal int, jsonEncode bool) error {
+	if jsonEncode {
+		if diffs == nil {
+			// encode as empty array rather than null
+			diffs = []funcDiff{}
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(diffs)
+	}
+
+	for _, d := range diffs {
+		fmt.Fprintln(w, d)
+	}
+
+	_, err := fmt.Fprintf(w, "Total: %d -> %d (%s)\n", oldTotal, newTotal, formatDelta(newTotal-oldTotal))
+	return err
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/uudashr/gocognit"
+)
+
+// runExplain runs the explain command, it shows the metrics, the
+// increments and the annotated source of a function.
+func runExplain(args []string, stdout, stderr io.Writer) int {
+	var (
+		rules      = gocognit.DefaultRules()
+		goIdiom    bool
+		halstead   bool
+		jsonEncode bool
+	)
+
+	fs := newFlagSet(explainCommand, stderr)
+	fs.Var(&rules, "rules", "comma separated name=increment pairs overriding the scoring rules")
+	fs.BoolVar(&goIdiom, "go-idiom", false, "do not count the trivial error checks")
+	fs.BoolVar(&halstead, "halstead", false, "calculate the Halstead metrics and the maintainability index")
+	fs.BoolVar(&jsonEncode, "json", false, "encode the output as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	fail := func(err error) int {
+		fmt.Fprintf(stderr, "gocognit explain: %v\n", err)
+		return 1
+	}
+
+	filename, funcName, ok := splitFuncRef(fs.Arg(0))
+	if !ok {
+		return fail(fmt.Errorf("%q is not in the form of <file>:<function>", fs.Arg(0)))
+	}
+
+	opts := gocognit.ScanOptions{
+		Diagnostics: true,
+		Rules:       &rules,
+
+		DiscountErrorChecks: goIdiom,
+		Halstead:            halstead,
+	}
+
+	// the source is read once so the annotated source is the analyzed one
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return fail(err)
+	}
+
+	stats, err := gocognit.AnalyzeSource(filename, b, opts)
+	if errs, ok := err.(gocognit.Errors); ok {
+		if err := writeErrors(stderr, errs, jsonEncode); err != nil {
+			return fail(err)
+		}
+	} else if err != nil {
+		return fail(err)
+	}
+
+	stat, ok := findStat(stats, funcName)
+	if !ok {
+		names := make([]string, 0, len(stats))
+		for _, s := range stats {
+			names = append(names, s.FuncName)
+		}
+
+		return fail(fmt.Errorf("no function %q in %s, the functions are: %s", funcName, filename, strings.Join(names, ", ")))
+	}
+
+	if jsonEncode {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "    ")
+		err = enc.Encode(stat)
+	} else {
+		err = writeExplanation(stdout, stat, sources{filename: b})
+	}
+
+	if err != nil {
+		return fail(err)
+	}
+
+	return 0
+}
+
+// splitFuncRef splits the <file>:<function> reference, the function name is
+// in the form of Stat.FuncName, such as Sum or (*Stack).Pop.
+func splitFuncRef(ref string) (filename, funcName string, ok bool) {
+	i := strings.LastIndex(ref, ":")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", false
+	}
+
+	return ref[:i], ref[i+1:], true
+}
+
+func findStat(stats []gocognit.Stat, funcName string) (gocognit.Stat, bool) {
+	for _, stat := range stats {
+		if stat.FuncName == funcName {
+			return stat, true
+		}
+	}
+
+	return gocognit.Stat{}, false
+}
+
+// writeExplanation writes the metrics of the function, then each
+// increment of the cognitive complexity and the annotated source.
+func writeExplanation(w io.Writer, stat gocognit.Stat, src sources) error {
+	fmt.Fprintf(w, "%s %s %s\n\n", stat.Pos, stat.PkgName, stat.FuncName)
+
+	fmt.Fprintf(w, "Metrics:\n")
+	for _, m := range []struct {
+		name  string
+		value int
+	}{
+		{"cognitive complexity", stat.Complexity},
+		{"cyclomatic complexity", stat.Cyclomatic},
+		{"max nesting", stat.MaxNesting},
+		{"statements", stat.Statements},
+		{"lines", stat.Lines},
+		{"params", stat.Params},
+		{"results", stat.Results},
+	} {
+		fmt.Fprintf(w, "  %-22s %d\n", m.name, m.value)
+	}
+
+	if h := stat.Halstead; h != nil {
+		fmt.Fprintf(w, "  %-22s %.1f\n", "halstead volume", h.Volume)
+		fmt.Fprintf(w, "  %-22s %.1f\n", "halstead difficulty", h.Difficulty)
+		fmt.Fprintf(w, "  %-22s %.1f\n", "halstead effort", h.Effort)
+		fmt.Fprintf(w, "  %-22s %.1f\n", "maintainability index", stat.MaintainabilityIndex)
+	}
+
+	fmt.Fprintf(w, "\nIncrements:\n")
+	if len(stat.Diagnostics) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	}
+
+	for _, diag := range stat.Diagnostics {
+		fmt.Fprintf(w, "  %-8s %-16s %s\n", diag.Pos, diag.String(), diag.Text)
+	}
+
+	fmt.Fprintf(w, "\nSource:\n")
+	return writeAnnotatedFunc(w, stat, src)
+}
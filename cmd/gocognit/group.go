@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/uudashr/gocognit"
+)
+
+const (
+	typeGroup    = "type"
+	packageGroup = "package"
+)
+
+// groupStats aggregates the stats by type or package, the groups with
+// total complexity > over are kept up to the top N.
+func groupStats(stats []gocognit.Stat, group string, top, over int) []gocognit.GroupStat {
+	var groups []gocognit.GroupStat
+	if group == typeGroup {
+		groups = gocognit.TypeStats(stats)
+	} else {
+		groups = gocognit.PackageStats(stats)
+	}
+
+	var filtered []gocognit.GroupStat
+	for _, g := range groups {
+		if len(filtered) == top {
+			break
+		}
+
+		if g.Complexity > over {
+			filtered = append(filtered, g)
+		}
+	}
+
+	return filtered
+}
+
+func writeGroupStats(w io.Writer, groups []gocognit.GroupStat, jsonEncode bool) (int, error) {
+	if jsonEncode {
+		if groups == nil {
+			// encode as empty array rather than null
+			groups = []gocognit.GroupStat{}
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		if err := enc.Encode(groups); err != nil {
+			return 0, err
+		}
+
+		return len(groups), nil
+	}
+
+	for _, g := range groups {
+		fmt.Fprintln(w, g)
+	}
+
+	return len(groups), nil
+}
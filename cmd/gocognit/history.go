@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/uudashr/gocognit"
+)
+
+const (
+	historyRecordCommand = "record"
+	historyShowCommand   = "show"
+)
+
+const defaultHistoryFile = ".gocognit-history.json"
+
+// now returns the time of the recorded runs, it is replaced by the tests.
+var now = time.Now
+
+// history is the file of the recorded runs, the oldest first.
+type history struct {
+	Records []historyRecord
+}
+
+// historyRecord is the summary of a run.
+type historyRecord struct {
+	Time       time.Time
+	Commit     string `json:",omitempty"`
+	Funcs      int
+	Complexity int
+	Packages   []gocognit.GroupStat
+	Functions  []historyFunc // every function, the most complex first
+}
+
+type historyFunc struct {
+	PkgName    string
+	FuncName   string
+	Filename   string
+	Complexity int
+}
+
+func (f historyFunc) key() string {
+	return f.Filename + "\x00" + f.PkgName + "\x00" + f.FuncName
+}
+
+// runHistory runs the history record and show commands.
+func runHistory(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "gocognit history: missing command, expecting record or show")
+		return 2
+	}
+
+	switch args[0] {
+	case historyRecordCommand:
+		return runHistoryRecord(args[1:], stdout, stderr)
+	case historyShowCommand:
+		return runHistoryShow(args[1:], stdout, stderr)
+	}
+
+	fmt.Fprintf(stderr, "gocognit history: unknown command %q, expecting record or show\n", args[0])
+	return 2
+}
+
+// runHistoryRecord analyzes the paths and appends the summary to the
+// history file.
+func runHistoryRecord(args []string, stdout, stderr io.Writer) int {
+	var (
+		file         string
+		commit       string
+		includeTests bool
+		ignoreExpr   string
+		excludes     stringsFlag
+		rules        = gocognit.DefaultRules()
+		goIdiom      bool
+	)
+
+	fs := newFlagSet(historyCommand+" "+historyRecordCommand, stderr)
+	fs.StringVar(&file, "file", defaultHistoryFile, "the history file")
+	fs.StringVar(&commit, "commit", "", "the commit id of the run")
+	fs.BoolVar(&includeTests, "test", true, "indicates whether test files should be included")
+	fs.StringVar(&ignoreExpr, "ignore", "", "ignore files matching the given regexp")
+	fs.Var(&excludes, "exclude", "skip the files and directories matching the glob pattern")
+	fs.Var(&rules, "rules", "comma separated name=increment pairs overriding the scoring rules")
+	fs.BoolVar(&goIdiom, "go-idiom", false, "do not count the trivial error checks")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	fail := func(err error) int {
+		fmt.Fprintf(stderr, "gocognit history: %v\n", err)
+		return 1
+	}
+
+	opts := gocognit.Options{
+		ScanOptions: gocognit.ScanOptions{
+			Rules:               &rules,
+			DiscountErrorChecks: goIdiom,
+		},
+		IncludeTests: includeTests,
+		Exclude:      excludes,
+	}
+
+	var err error
+	if opts.Ignore, err = prepareRegexp(ignoreExpr); err != nil {
+		return fail(err)
+	}
+
+	stats, err := gocognit.AnalyzePaths(context.Background(), fs.Args(), opts)
+	if errs, ok := err.(gocognit.Errors); ok {
+		err = writeErrors(stderr, errs, false)
+	}
+
+	if err != nil {
+		return fail(err)
+	}
+
+	h, err := readHistory(file)
+	if err != nil {
+		return fail(err)
+	}
+
+	rec := newHistoryRecord(stats, commit)
+	h.Records = append(h.Records, rec)
+
+	if err := writeHistory(file, h); err != nil {
+		return fail(err)
+	}
+
+	fmt.Fprintf(stdout, "recorded %d functions, total complexity %d\n", rec.Funcs, rec.Complexity)
+	return 0
+}
+
+func newHistoryRecord(stats []gocognit.Stat, commit string) historyRecord {
+	rec := historyRecord{
+		Time:     now().UTC(),
+		Commit:   commit,
+		Funcs:    len(stats),
+		Packages: gocognit.PackageStats(stats),
+	}
+
+	for _, stat := range stats {
+		rec.Complexity += stat.Complexity
+	}
+
+	sorted := append([]gocognit.Stat(nil), stats...)
+	sort.Stable(byComplexity(sorted))
+
+	for _, stat := range sorted {
+		rec.Functions = append(rec.Functions, historyFunc{
+			PkgName:    stat.PkgName,
+			FuncName:   stat.FuncName,
+			Filename:   stat.Pos.Filename,
+			Complexity: stat.Complexity,
+		})
+	}
+
+	return rec
+}
+
+// runHistoryShow prints the trend of the recorded runs and the functions
+// which complexity grew the most between the first and the last of them.
+func runHistoryShow(args []string, stdout, stderr io.Writer) int {
+	var (
+		file       string
+		last       int
+		top        int
+		jsonEncode bool
+	)
+
+	fs := newFlagSet(historyCommand+" "+historyShowCommand, stderr)
+	fs.StringVar(&file, "file", defaultHistoryFile, "the history file")
+	fs.IntVar(&last, "n", 10, "show the last N records")
+	fs.IntVar(&top, "top", 10, "show the top N grown functions")
+	fs.BoolVar(&jsonEncode, "json", false, "encode the output as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	fail := func(err error) int {
+		fmt.Fprintf(stderr, "gocognit history: %v\n", err)
+		return 1
+	}
+
+	h, err := readHistory(file)
+	if err != nil {
+		return fail(err)
+	}
+
+	records := h.Records
+	if last > 0 && last < len(records) {
+		records = records[len(records)-last:]
+	}
+
+	var grown []funcDiff
+	if len(records) > 1 {
+		grown = grownFuncs(records[0], records[len(records)-1])
+		if top >= 0 && top < len(grown) {
+			grown = grown[:top]
+		}
+	}
+
+	if jsonEncode {
+		if records == nil {
+			// encode as empty array rather than null
+			records = []historyRecord{}
+		}
+
+		if grown == nil {
+			grown = []funcDiff{}
+		}
+
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "    ")
+		err = enc.Encode(struct {
+			Records []historyRecord
+			Grown   []funcDiff
+		}{records, grown})
+	} else {
+		err = writeHistoryTrend(stdout, records, grown)
+	}
+
+	if err != nil {
+		return fail(err)
+	}
+
+	return 0
+}
+
+// grownFuncs returns the functions which complexity grew, the most grown
+// first. The functions added after the first record are left out.
+func grownFuncs(from, to historyRecord) []funcDiff {
+	old := make(map[string]int, len(from.Functions))
+	for _, f := range from.Functions {
+		old[f.key()] = f.Complexity
+	}
+
+	var grown []funcDiff
+	for _, f := range to.Functions {
+		prev, ok := old[f.key()]
+		if !ok || f.Complexity <= prev {
+			continue
+		}
+
+		grown = append(grown, funcDiff{
+			PkgName:  f.PkgName,
+			FuncName: f.FuncName,
+			Pos:      token.Position{Filename: f.Filename},
+			Status:   diffChanged,
+			Old:      prev,
+			New:      f.Complexity,
+			Delta:    f.Complexity - prev,
+		})
+	}
+
+	sort.SliceStable(grown, func(i, j int) bool {
+		return grown[i].Delta > grown[j].Delta
+	})
+
+	return grown
+}
+
+func writeHistoryTrend(w io.Writer, records []historyRecord, grown []funcDiff) error {
+	if len(records) == 0 {
+		_, err := fmt.Fprintln(w, "no records")
+		return err
+	}
+
+	for i, rec := range records {
+		commit := rec.Commit
+		if commit == "" {
+			commit = "-"
+		}
+
+		fmt.Fprintf(w, "%s %s funcs=%d complexity=%d", rec.Time.Format(time.RFC3339), commit, rec.Funcs, rec.Complexity)
+		if i > 0 {
+			fmt.Fprintf(w, " (%s)", formatDelta(rec.Complexity-records[i-1].Complexity))
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	if len(records) < 2 {
+		return nil
+	}
+
+	first, latest := records[0], records[len(records)-1]
+
+	fmt.Fprintf(w, "\nPackages:\n")
+	base := make(map[string]int, len(first.Packages))
+	for _, g := range first.Packages {
+		base[g.Dir+"\x00"+g.PkgName] = g.Complexity
+	}
+
+	for _, g := range latest.Packages {
+		prev := base[g.Dir+"\x00"+g.PkgName]
+		fmt.Fprintf(w, "  %s (%s) %d -> %d (%s)\n", g.Dir, g.PkgName, prev, g.Complexity, formatDelta(g.Complexity-prev))
+	}
+
+	fmt.Fprintf(w, "\nGrown the most:\n")
+	if len(grown) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	}
+
+	for _, d := range grown {
+		fmt.Fprintf(w, "  %s %s %s %s (%d -> %d)\n", formatDelta(d.Delta), d.PkgName, d.FuncName, d.Pos.Filename, d.Old, d.New)
+	}
+
+	return nil
+}
+
+// readHistory reads the history file, a missing file is an empty history.
+func readHistory(filename string) (history, error) {
+	var h history
+
+	b, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+
+	if err != nil {
+		return h, err
+	}
+
+	if err := json.Unmarshal(b, &h); err != nil {
+		return h, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return h, nil
+}
+
+// writeHistory replaces the history file by renaming a temporary file, so
+// the file is never left partially written.
+func writeHistory(filename string, h history) error {
+	b, err := json.MarshalIndent(h, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	// removes the temporary file on failure, it no longer exists once it
+	// is renamed
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), filename)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGrownFuncs(t *testing.T) {
+	from := historyRecord{Functions: []historyFunc{
+		{PkgName: "p", FuncName: "Big", Filename: "p/p.go", Complexity: 20},
+		{PkgName: "p", FuncName: "Small", Filename: "p/p.go", Complexity: 0},
+		{PkgName: "p", FuncName: "Removed", Filename: "p/p.go", Complexity: 3},
+	}}
+	to := historyRecord{Functions: []historyFunc{
+		{PkgName: "p", FuncName: "Big", Filename: "p/p.go", Complexity: 21},
+		{PkgName: "p", FuncName: "Added", Filename: "p/p.go", Complexity: 9},
+		{PkgName: "p", FuncName: "Small", Filename: "p/p.go", Complexity: 4},
+	}}
+
+	grown := grownFuncs(from, to)
+
+	var got []string
+	for _, d := range grown {
+		got = append(got, d.String())
+	}
+
+	want := []string{
+		"+4 p Small p/p.go (0 -> 4)",
+		"+1 p Big p/p.go (20 -> 21)",
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %q, want %q", got[i], want[i])
+		}
+	}
+}
+
+func TestWriteHistory(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "history.json")
+
+	for i := 1; i <= 2; i++ {
+		h := history{Records: make([]historyRecord, i)}
+		if err := writeHistory(file, h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h, err := readHistory(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(h.Records) != 2 {
+		t.Errorf("got %d records, want 2", len(h.Records))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("got %d files, want only the history file", len(entries))
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mode := info.Mode().Perm(); mode != 0o644 {
+		t.Errorf("got mode %v, want -rw-r--r--", mode)
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"html/template"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/uudashr/gocognit"
+)
+
+// maxHeatLevel is the highest nesting level that has its own color in the
+// heat map, deeper levels share the same color.
+const maxHeatLevel = 5
+
+type htmlReport struct {
+	Total     int
+	Average   float64
+	Packages  []htmlPackage
+	Files     []htmlFile
+	Functions []htmlFunc
+}
+
+type htmlPackage struct {
+	Name       string
+	Dir        string
+	Funcs      int
+	Total      int
+	Max        int
+	Average    float64
+	MaxFuncRef string
+}
+
+type htmlFile struct {
+	Name  string
+	Total int
+	Funcs []htmlFunc
+}
+
+type htmlFunc struct {
+	ID    string
+	Stat  gocognit.Stat
+	Lines []htmlLine
+	Err   string
+}
+
+type htmlLine struct {
+	Num  int
+	Code string
+	Incs string
+	Heat int
+}
+
+func writeHTMLStats(w io.Writer, stats []gocognit.Stat, src sources) (int, error) {
+	report := buildHTMLReport(stats, src)
+	if err := htmlTemplate.Execute(w, report); err != nil {
+		return 0, err
+	}
+
+	return len(stats), nil
+}
+
+func buildHTMLReport(stats []gocognit.Stat, src sources) htmlReport {
+	var report htmlReport
+
+	pkgIndex := make(map[string]int)
+	fileIndex := make(map[string]int)
+
+	for i, stat := range stats {
+		fn := htmlFunc{
+			ID:   fmt.Sprintf("fn-%d", i),
+			Stat: stat,
+		}
+
+		lines, err := htmlSourceLines(stat, src)
+		if err != nil {
+			fn.Err = err.Error()
+		}
+		fn.Lines = lines
+
+		report.Functions = append(report.Functions, fn)
+		report.Total += stat.Complexity
+
+		key := pkgKey(stat)
+		idx, ok := pkgIndex[key]
+		if !ok {
+			idx = len(report.Packages)
+			pkgIndex[key] = idx
+			report.Packages = append(report.Packages, htmlPackage{
+				Name: stat.PkgName,
+				Dir:  filepath.Dir(stat.Pos.Filename),
+			})
+		}
+
+		pkg := &report.Packages[idx]
+		pkg.Funcs++
+		pkg.Total += stat.Complexity
+		if pkg.MaxFuncRef == "" || stat.Complexity > pkg.Max {
+			pkg.Max = stat.Complexity
+			pkg.MaxFuncRef = fn.ID
+		}
+
+		idx, ok = fileIndex[stat.Pos.Filename]
+		if !ok {
+			idx = len(report.Files)
+			fileIndex[stat.Pos.Filename] = idx
+			report.Files = append(report.Files, htmlFile{Name: stat.Pos.Filename})
+		}
+
+		file := &report.Files[idx]
+		file.Total += stat.Complexity
+		file.Funcs = append(file.Funcs, fn)
+	}
+
+	for i := range report.Packages {
+		pkg := &report.Packages[i]
+		pkg.Average = float64(pkg.Total) / float64(pkg.Funcs)
+	}
+
+	if len(stats) > 0 {
+		report.Average = float64(report.Total) / float64(len(stats))
+	}
+
+	sort.SliceStable(report.Packages, func(i, j int) bool {
+		return report.Packages[i].Total > report.Packages[j].Total
+	})
+
+	sort.SliceStable(report.Files, func(i, j int) bool {
+		return report.Files[i].Name < report.Files[j].Name
+	})
+
+	return report
+}
+
+// htmlSourceLines returns the source lines of the function shaded by their
+// nesting depth, with the increments of each line.
+func htmlSourceLines(stat gocognit.Stat, srcs sources) ([]htmlLine, error) {
+	src, err := funcSourceLines(stat, srcs)
+	if err != nil {
+		return nil, err
+	}
+
+	depths, err := lineDepths(stat, srcs)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]htmlLine, len(src))
+	for i, code := range src {
+		heat := depths[i]
+		if heat > maxHeatLevel {
+			heat = maxHeatLevel
+		}
+
+		lines[i] = htmlLine{
+			Num:  stat.Pos.Line + i,
+			Code: code,
+			Heat: heat,
+		}
+	}
+
+	for _, diag := range stat.Diagnostics {
+		i := diag.Pos.Line - stat.Pos.Line
+		if i < 0 || i >= len(lines) {
+			continue
+		}
+
+		line := &lines[i]
+		if line.Incs != "" {
+			line.Incs += ", "
+		}
+
+		line.Incs += fmt.Sprintf("%s %s", diag, diag.Text)
+	}
+
+	return lines, nil
+}
+
+// lineDepths returns the nesting depth of each line of the function, the
+// number of the blocks of the nesting structures, such as if, for, switch
+// and function literals, around the line. The opening and the closing
+// lines of a block are at the depth of its structure.
+func lineDepths(stat gocognit.Stat, srcs sources) ([]int, error) {
+	src, err := srcs.read(stat.Pos.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	start := bytes.LastIndexByte(src[:stat.Pos.Offset], '\n') + 1
+
+	// the function is parsed alone, it starts at the second line
+	fset := token.NewFileSet()
+	code := append([]byte("package p\n"), src[start:stat.End.Offset]...)
+	f, err := parser.ParseFile(fset, "", code, 0)
+	if err != nil || len(f.Decls) == 0 {
+		return nil, fmt.Errorf("%s: can not parse %s", stat.Pos, stat.FuncName)
+	}
+
+	depths := make([]int, stat.End.Line-stat.Pos.Line+1)
+	nest := func(block ast.Node) {
+		from, to := fset.Position(block.Pos()).Line-2, fset.Position(block.End()).Line-2
+		for i := from + 1; i < to && i < len(depths); i++ {
+			depths[i]++
+		}
+	}
+
+	ast.Inspect(f.Decls[0], func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt:
+			nest(n.Body)
+			if els, ok := n.Else.(*ast.BlockStmt); ok {
+				nest(els)
+			}
+		case *ast.ForStmt:
+			nest(n.Body)
+		case *ast.RangeStmt:
+			nest(n.Body)
+		case *ast.SwitchStmt:
+			nest(n.Body)
+		case *ast.TypeSwitchStmt:
+			nest(n.Body)
+		case *ast.SelectStmt:
+			nest(n.Body)
+		case *ast.FuncLit:
+			nest(n.Body)
+		}
+
+		return true
+	})
+
+	return depths, nil
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"avg": func(f float64) string {
+		return fmt.Sprintf("%.3g", f)
+	},
+}).Parse(htmlTemplateText))
+
+const htmlTemplateText = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>gocognit report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.2em 0.6em; text-align: left; }
+th.sortable { cursor: pointer; background: #f0f0f0; }
+td.num { text-align: right; }
+table.source { border: none; font-family: monospace; }
+table.source td { border: none; padding: 0 0.6em; white-space: pre; }
+table.source td.ln { color: #999; text-align: right; }
+table.source td.inc { color: #a00; }
+table.source td.ln.inc { color: #a00; font-weight: bold; }
+tr.heat-1 { background: #fff5d6; }
+tr.heat-2 { background: #ffe3a3; }
+tr.heat-3 { background: #ffc878; }
+tr.heat-4 { background: #ffa25c; }
+tr.heat-5 { background: #ff7a50; }
+</style>
+</head>
+<body>
+<h1>gocognit report</h1>
+<p>{{len .Functions}} functions, total complexity {{.Total}}, average {{avg .Average}}</p>
+
+<h2>Packages</h2>
+<table class="sortable">
+<thead><tr><th class="sortable">Package</th><th class="sortable">Directory</th><th class="sortable">Functions</th><th class="sortable">Total</th><th class="sortable">Max</th><th class="sortable">Average</th></tr></thead>
+<tbody>
+{{- range .Packages}}
+<tr><td>{{.Name}}</td><td>{{.Dir}}</td><td class="num">{{.Funcs}}</td><td class="num">{{.Total}}</td><td class="num"><a href="#{{.MaxFuncRef}}">{{.Max}}</a></td><td class="num">{{avg .Average}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+
+<h2>Files</h2>
+{{- range .Files}}
+<h3>{{.Name}} ({{.Total}})</h3>
+<table class="sortable">
+<thead><tr><th class="sortable">Function</th><th class="sortable">Complexity</th><th class="sortable">Cyclomatic</th><th class="sortable">Line</th></tr></thead>
+<tbody>
+{{- range .Funcs}}
+<tr><td><a href="#{{.ID}}">{{.Stat.FuncName}}</a></td><td class="num">{{.Stat.Complexity}}</td><td class="num">{{.Stat.Cyclomatic}}</td><td class="num">{{.Stat.Pos.Line}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+
+<h2>Functions</h2>
+{{- range .Functions}}
+<h3 id="{{.ID}}">{{.Stat.PkgName}} {{.Stat.FuncName}}: {{.Stat.Complexity}}</h3>
+<p>{{.Stat.Pos}}, cyclomatic complexity {{.Stat.Cyclomatic}}</p>
+{{- if .Err}}
+<p>source unavailable: {{.Err}}</p>
+{{- else}}
+<table class="source">
+{{- range .Lines}}
+<tr{{if .Heat}} class="heat-{{.Heat}}"{{end}}><td class="ln{{if .Incs}} inc{{end}}">{{.Num}}</td><td>{{.Code}}</td><td class="inc">{{.Incs}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th.sortable").forEach(function (th, col) {
+    var asc = false;
+    th.addEventListener("click", function () {
+      var tbody = table.tBodies[0];
+      var rows = Array.prototype.slice.call(tbody.rows);
+      asc = !asc;
+      rows.sort(function (a, b) {
+        var x = a.cells[col].textContent, y = b.cells[col].textContent;
+        var nx = parseFloat(x), ny = parseFloat(y);
+        var cmp = isNaN(nx) || isNaN(ny) ? x.localeCompare(y) : nx - ny;
+        return asc ? cmp : -cmp;
+      });
+      rows.forEach(function (row) { tbody.appendChild(row); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+`
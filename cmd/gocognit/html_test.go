@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const htmlSrc = `package p
+
+func Walk(rows [][]int, f func(int) bool) int {
+	n := 0
+	for _, row := range rows {
+		for _, v := range row {
+			if f(v) {
+				n++
+			} else {
+				n--
+			}
+		}
+	}
+	return n
+}
+`
+
+func TestHTMLSourceLines(t *testing.T) {
+	stats := analyzeTestSource(t, "p.go", htmlSrc)
+	src := sources{"p.go": []byte(htmlSrc)}
+
+	lines, err := htmlSourceLines(stats[0], src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every line is shaded by its nesting depth, not only the ones with
+	// increments
+	wantHeat := []int{0, 0, 0, 1, 2, 3, 2, 3, 2, 1, 0, 0, 0}
+	if len(lines) != len(wantHeat) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(wantHeat))
+	}
+
+	for i, line := range lines {
+		if line.Heat != wantHeat[i] {
+			t.Errorf("line %d %q: got heat %d, want %d", line.Num, line.Code, line.Heat, wantHeat[i])
+		}
+	}
+
+	wantIncs := map[int]string{
+		5: "+1 for",
+		6: "+2 (nesting=1) for",
+		7: "+3 (nesting=2) if",
+		9: "+1 else",
+	}
+	for _, line := range lines {
+		if got := line.Incs; got != wantIncs[line.Num] {
+			t.Errorf("line %d: got increments %q, want %q", line.Num, got, wantIncs[line.Num])
+		}
+	}
+}
+
+func TestWriteHTMLStats(t *testing.T) {
+	stats := analyzeTestSource(t, "p.go", htmlSrc)
+
+	var buf bytes.Buffer
+	if _, err := writeHTMLStats(&buf, stats, sources{"p.go": []byte(htmlSrc)}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		`<tr class="heat-2"><td class="ln inc">7</td><td>            if f(v) {</td><td class="inc">&#43;3 (nesting=2) if</td></tr>`,
+		`<tr class="heat-3"><td class="ln">8</td><td>                n&#43;&#43;</td><td class="inc"></td></tr>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("got output without %q", want)
+		}
+	}
+
+	// fully offline
+	for _, asset := range []string{"http://", "https://", "<link"} {
+		if strings.Contains(out, asset) {
+			t.Errorf("got output with external asset %q", asset)
+		}
+	}
+}
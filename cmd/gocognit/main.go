@@ -3,17 +3,61 @@
 //
 // Usage:
 //
-//	gocognit [<flag> ...] <Go file or directory> ...
+//	gocognit [<command>] [<flag> ...] <Go file or directory> ...
+//	gocognit [<command>] [<flag> ...] -stdin [-stdin-filename name]
+//	gocognit explain [-rules list] [-go-idiom] [-halstead] [-json] <file>:<function>
+//	gocognit diff [<flag> ...] <old.json> <new.json | Go file or directory ...>
+//	gocognit history record [-file f] [-commit id] [<flag> ...] <Go file or directory> ...
+//	gocognit history show [-file f] [-n N] [-top N] [-json]
+//
+// Commands:
+//
+//	check    show the functions exceeding the limits and return exit code 1 if there are any, a limit must be set
+//	report   show the stats in any format, the exit code is 0 even when a limit is exceeded
+//	explain  show the metrics, the complexity increments and the annotated source of a function
+//	diff     compare the JSON output of a previous run with another one or with the current stats
+//	history  record the summary of a run to the history file, or show the trend of the recorded runs
+//
+// Without a command, the flags behave as check when a limit is set and as
+// report otherwise. A file or directory with the name of a command is
+// analyzed as a path.
 //
 // Flags:
 //
 //	-over N    show functions with complexity > N only and return exit code 1 if the output is non-empty
+//	-cyclo-over N  show functions with cyclomatic complexity > N only and return exit code 1 if the output is non-empty
+//	-max-nesting N     show functions with nesting depth > N only and return exit code 1 if the output is non-empty
+//	-max-statements N  show functions with more than N statements only and return exit code 1 if the output is non-empty
+//	-max-lines N       show functions longer than N lines only and return exit code 1 if the output is non-empty
+//	-max-params N      show functions with more than N parameters only and return exit code 1 if the output is non-empty
+//	-max-results N     show functions with more than N results only and return exit code 1 if the output is non-empty
+//	-warn N    show functions with complexity > N as warnings, they do not change the exit code
+//	-error N   show functions with complexity > N as errors and return exit code 1 if there are any
 //	-top N     show the top N most complex functions only
 //	-avg       show the average complexity over all functions, not depending on whether -over or -top are set
 //	-test      indicates whether test files should be included
-//	-json      encode the output as JSON
+//	-json      encode the output as JSON, equal to -format json
+//	-format    the output format: text, json, json-report, csv, tsv, markdown, html, treemap, treemap-csv or sarif (default "text")
+//	-baseline  the JSON output of a previous run to compare with, used by markdown format
 //	-d 	       enable diagnostic output
+//	-annotate  print the source of the functions annotated with the complexity increments
+//	-rules     comma separated name=increment pairs overriding the scoring rules, e.g. "else=0,closure=0"
+//	-go-idiom  do not count the trivial error checks (if err != nil { return ... })
+//	-halstead  calculate the Halstead metrics and the maintainability index
+//	-group kind  show the total complexity of each type or package instead, kind is type or package
+//	-budget-package N  show the packages which total complexity > N too and return exit code 1 if there are any
+//	-budget-file N     show the files which total complexity > N too and return exit code 1 if there are any
+//	-budget-dir N      show the directories which total complexity > N too and return exit code 1 if there are any
+//	-config file       the JSON configuration file with the budgets of the scopes and paths
 //	-f format  string the format to use (default "{{.Complexity}} {{.PkgName}} {{.FuncName}} {{.Pos}}")
+//	-include-func expr  show the functions which name matches the regexp only
+//	-exclude-func expr  do not show the functions which name matches the regexp, e.g. "\.(String|MarshalJSON)$"
+//	-pkg expr  show the packages which name matches the regexp only
+//	-exported-only  show the exported functions and the exported methods of the exported types only
+//	-exclude pattern  skip the files and directories matching the glob pattern, can be repeated
+//	-strict    stop at the first file which can not be parsed, instead of reporting the errors and analyzing the other functions
+//	-stdin     read the Go source from the standard input, e.g. an unsaved editor buffer
+//	-stdin-filename name  the file name reported for the standard input (default "<stdin>")
 //
 // The (default) output fields for each line are:
 //
@@ -31,8 +75,28 @@
 //	  PkgName    string
 //	  FuncName   string
 //	  Complexity int
+//	  Cyclomatic int
+//	  MaxNesting int
+//	  Statements int
+//	  Lines      int
+//	  Params     int
+//	  Results    int
 //	  Diagnostics []Diagnostic
 //	  Pos        token.Position
+//	  End        token.Position
+//	  Halstead   *Halstead
+//	  MaintainabilityIndex float64
+//	  Severity   string
+//	}
+//
+//	type Halstead struct {
+//	  DistinctOperators int
+//	  DistinctOperands  int
+//	  Operators         int
+//	  Operands          int
+//	  Volume            float64
+//	  Difficulty        float64
+//	  Effort            float64
 //	}
 //
 //	type Diagnostic struct {
@@ -50,17 +114,14 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"go/parser"
-	"go/token"
 	"io"
-	"log"
 	"os"
-	"path/filepath"
 	"regexp"
-	"sort"
 	"strings"
 	"text/template"
 
@@ -71,21 +132,122 @@ const usageDoc = `Calculate cognitive complexities of Go functions.
 
 Usage:
 
-  gocognit [<flag> ...] <Go file or directory> ...
+  gocognit [<command>] [<flag> ...] <Go file or directory> ...
+  gocognit [<command>] [<flag> ...] -stdin [-stdin-filename name]
+  gocognit explain [-rules list] [-go-idiom] [-halstead] [-json] <file>:<function>
+  gocognit diff [<flag> ...] <old.json> <new.json | Go file or directory ...>
+  gocognit history record [-file f] [-commit id] [<flag> ...] <Go file or directory> ...
+  gocognit history show [-file f] [-n N] [-top N] [-json]
+
+Commands:
+
+  check         show the functions exceeding the limits and return
+                exit code 1 if there are any, a limit must be set
+  report        show the stats in any format, the exit code is 0
+                even when a limit is exceeded
+  explain       show the metrics, the complexity increments and the
+                annotated source of a function, e.g. p.go:(*T).Run
+  diff          compare the JSON output of a previous run with another
+                one or with the current stats, the flags are -test,
+                -json, -ignore, -exclude, -rules and -go-idiom
+  history record
+                append the summary of a run, its time, the -commit id,
+                the package totals and the complexity of every
+                function, to the -file history file
+                (default ".gocognit-history.json"), the flags are
+                the ones of diff except -json
+  history show  show the trend of the last -n N (default 10) records
+                and the -top N (default 10) functions which complexity
+                grew the most between the first and the last of them
+
+  Without a command, the flags behave as check when a limit is set
+  and as report otherwise. A file or directory with the name of a
+  command is analyzed as a path.
 
 Flags:
 
   -over N       show functions with complexity > N only
                 and return exit code 1 if the output is non-empty
+  -cyclo-over N show functions with cyclomatic complexity > N only
+                and return exit code 1 if the output is non-empty
+  -max-nesting N
+                show functions with nesting depth > N only
+  -max-statements N
+                show functions with more than N statements only
+  -max-lines N  show functions longer than N lines only
+  -max-params N show functions with more than N parameters only
+  -max-results N
+                show functions with more than N results only
+                (all of them return exit code 1 if the output is non-empty)
+  -warn N       show functions with complexity > N as warnings,
+                they do not change the exit code
+  -error N      show functions with complexity > N as errors
+                and return exit code 1 if there are any
+                (the severity is prepended to the default text output)
   -top N        show the top N most complex functions only
   -avg          show the average complexity over all functions,
                 not depending on whether -over or -top are set
   -test         indicates whether test files should be included
-  -json         encode the output as JSON
+  -json         encode the output as JSON, equal to -format json
+  -format name  the output format: text, json, json-report, csv,
+                tsv, markdown, html, treemap, treemap-csv or sarif
+                (default "text"), json-report is the JSON document
+                of the stats with the errors of the files
+  -baseline file
+                the JSON output of a previous run to compare with,
+                used by markdown format
   -d 	        enable diagnostic output
+  -annotate     print the source of the functions annotated with
+                the complexity increments
+  -rules list   comma separated name=increment pairs overriding
+                the scoring rules, e.g. "else=0,closure=0"
+  -go-idiom     do not count the trivial error checks
+                (if err != nil { return ... })
+  -halstead     calculate the Halstead metrics and the maintainability
+                index, they are appended to the default text output
+  -group kind   show the total, maximum and average complexity of each
+                type or package instead, kind is "type" or "package",
+                only -over, -top and -ignore apply to the groups
+  -budget-package N
+                show the packages which total complexity > N, with
+                their most complex functions, after the functions
+                exceeding the limits, and return exit code 1 if there
+                are any of both
+  -budget-file N
+                the same for the files
+  -budget-dir N the same for the directories, including the functions
+                of their sub directories
+  -config file  the JSON configuration file, it has the default budgets
+                of the scopes and the budgets of the paths, e.g.
+                {"budgets": {"package": {"default": 200,
+                "paths": {"internal/legacy": 400}}}}, the -budget
+                flags override the defaults
   -f format     string the format to use 
                 (default "{{.Complexity}} {{.PkgName}} {{.FuncName}} {{.Pos}}")
   -ignore expr  ignore files matching the given regexp
+  -include-func expr
+                show the functions which name matches the regexp only
+  -exclude-func expr
+                do not show the functions which name matches the regexp,
+                e.g. "\.(String|MarshalJSON)$"
+  -pkg expr     show the packages which name matches the regexp only
+  -exported-only
+                show the exported functions and the exported methods
+                of the exported types only
+  -exclude pattern
+                skip the files and directories matching the glob
+                pattern before parsing, matched against the base name
+                and the path relative to the walked directory,
+                can be repeated, e.g. -exclude "*_gen.go"
+  -strict       stop at the first file which can not be read or parsed,
+                by default the errors are reported to stderr, as JSON
+                with -json, also in the Errors field of -format
+                json-report, and the other functions are still analyzed
+  -stdin        read the Go source from the standard input,
+                e.g. an unsaved editor buffer
+  -stdin-filename name
+                the file name reported for the standard input
+                (default "<stdin>")
 
 The (default) output fields for each line are:
 
@@ -103,8 +265,28 @@ The struct being passed to the template is:
     PkgName     string
     FuncName    string
     Complexity  int
+    Cyclomatic  int
+    MaxNesting  int
+    Statements  int
+    Lines       int
+    Params      int
+    Results     int
     Pos         token.Position
+    End         token.Position
     Diagnostics []Diagnostics
+    Halstead    *Halstead
+    MaintainabilityIndex float64
+    Severity    string
+  }
+
+  type Halstead struct {
+    DistinctOperators int
+    DistinctOperands  int
+    Operators         int
+    Operands          int
+    Volume            float64
+    Difficulty        float64
+    Effort            float64
   }
 
   type Diagnostic struct {
@@ -126,155 +308,412 @@ const (
 	defaultTopFlagVal  = -1
 )
 
+const defaultStdinFilename = "<stdin>"
+
+const (
+	checkCommand   = "check"
+	reportCommand  = "report"
+	explainCommand = "explain"
+	diffCommand    = "diff"
+	historyCommand = "history"
+)
+
 const defaultFormat = "{{.Complexity}} {{.PkgName}} {{.FuncName}} {{.Pos}}"
 
-func usage() {
-	_, _ = fmt.Fprint(os.Stderr, usageDoc)
-	os.Exit(2)
-}
+const halsteadFormat = defaultFormat + ` volume={{printf "%.1f" .Halstead.Volume}}` +
+	` difficulty={{printf "%.1f" .Halstead.Difficulty}}` +
+	` effort={{printf "%.1f" .Halstead.Effort}}` +
+	` mi={{printf "%.1f" .MaintainabilityIndex}}`
+
+// severityPrefix is prepended to the default format when -warn or -error
+// is set.
+const severityPrefix = "{{with .Severity}}{{.}}: {{end}}"
+
+const (
+	textOutputFormat = "text"
+	jsonOutputFormat = "json"
+	csvOutputFormat  = "csv"
+	tsvOutputFormat  = "tsv"
+	htmlOutputFormat = "html"
+
+	// jsonReportOutputFormat is the JSON document of the stats with the
+	// errors of the files, -json keeps writing the array of the stats.
+	jsonReportOutputFormat = "json-report"
+
+	sarifOutputFormat = "sarif"
+
+	markdownOutputFormat = "markdown"
+
+	treemapOutputFormat    = "treemap"
+	treemapCSVOutputFormat = "treemap-csv"
+)
 
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command with the arguments, without the program name, and
+// returns the exit code: 0 on success, 1 when a limit is exceeded or on
+// errors, and 2 on invalid usage. Without a command the stats are shown as
+// by the check command, when no limit is set as by the report command.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && !pathExists(args[0]) {
+		switch args[0] {
+		case checkCommand, reportCommand:
+			return runStats(args[0], args[1:], stdin, stdout, stderr)
+		case explainCommand:
+			return runExplain(args[1:], stdout, stderr)
+		case diffCommand:
+			return runDiff(args[1:], stdout, stderr)
+		case historyCommand:
+			return runHistory(args[1:], stdout, stderr)
+		}
+	}
+
+	return runStats("", args, stdin, stdout, stderr)
+}
+
+// pathExists reports whether the file or directory exists, a path with
+// the name of a command is analyzed as the path for compatibility.
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// runStats runs the check and the report commands, or the command without
+// a name.
+func runStats(command string, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
 	var (
 		over              int
+		cycloOver         int
+		maxNesting        int
+		maxStatements     int
+		maxLines          int
+		maxParams         int
+		maxResults        int
+		warn              int
+		errorLevel        int
 		top               int
 		avg               bool
 		includeTests      bool
 		format            string
 		jsonEncode        bool
+		outputFormat      string
+		baselineFile      string
 		enableDiagnostics bool
+		annotate          bool
 		ignoreExpr        string
+		rules             = gocognit.DefaultRules()
+		goIdiom           bool
+		halstead          bool
+		group             string
+		budgetPackage     int
+		budgetFile        int
+		budgetDir         int
+		configFile        string
+		includeFuncExpr   string
+		excludeFuncExpr   string
+		pkgExpr           string
+		exportedOnly      bool
+		excludes          stringsFlag
+		strict            bool
+		readStdin         bool
+		stdinFilename     string
 	)
 
-	flag.IntVar(&over, "over", defaultOverFlagVal, "show functions with complexity > N only")
-	flag.IntVar(&top, "top", defaultTopFlagVal, "show the top N most complex functions only")
-	flag.BoolVar(&avg, "avg", false, "show the average complexity")
-	flag.BoolVar(&includeTests, "test", true, "indicates whether test files should be included")
-	flag.StringVar(&format, "f", defaultFormat, "the format to use")
-	flag.BoolVar(&jsonEncode, "json", false, "encode the output as JSON")
-	flag.BoolVar(&enableDiagnostics, "d", false, "enable diagnostic output")
-	flag.StringVar(&ignoreExpr, "ignore", "", "ignore files matching the given regexp")
-
-	log.SetFlags(0)
-	log.SetPrefix("gocognit: ")
-
-	flag.Usage = usage
-	flag.Parse()
-	args := flag.Args()
-
-	if len(args) == 0 {
-		usage()
+	fs := newFlagSet(command, stderr)
+
+	fs.IntVar(&over, "over", defaultOverFlagVal, "show functions with complexity > N only")
+	fs.IntVar(&cycloOver, "cyclo-over", defaultOverFlagVal, "show functions with cyclomatic complexity > N only")
+	fs.IntVar(&maxNesting, "max-nesting", 0, "show functions with nesting depth > N only")
+	fs.IntVar(&maxStatements, "max-statements", 0, "show functions with more than N statements only")
+	fs.IntVar(&maxLines, "max-lines", 0, "show functions longer than N lines only")
+	fs.IntVar(&maxParams, "max-params", 0, "show functions with more than N parameters only")
+	fs.IntVar(&maxResults, "max-results", 0, "show functions with more than N results only")
+	fs.IntVar(&warn, "warn", 0, "show functions with complexity > N as warnings")
+	fs.IntVar(&errorLevel, "error", 0, "show functions with complexity > N as errors and return exit code 1")
+	fs.IntVar(&top, "top", defaultTopFlagVal, "show the top N most complex functions only")
+	fs.BoolVar(&avg, "avg", false, "show the average complexity")
+	fs.BoolVar(&includeTests, "test", true, "indicates whether test files should be included")
+	fs.StringVar(&format, "f", defaultFormat, "the format to use")
+	fs.BoolVar(&jsonEncode, "json", false, "encode the output as JSON")
+	fs.StringVar(&outputFormat, "format", textOutputFormat, "the output format")
+	fs.StringVar(&baselineFile, "baseline", "", "the JSON output of a previous run to compare with")
+	fs.BoolVar(&enableDiagnostics, "d", false, "enable diagnostic output")
+	fs.BoolVar(&annotate, "annotate", false, "print the annotated source of the functions")
+	fs.StringVar(&ignoreExpr, "ignore", "", "ignore files matching the given regexp")
+	fs.Var(&rules, "rules", "comma separated name=increment pairs overriding the scoring rules")
+	fs.BoolVar(&goIdiom, "go-idiom", false, "do not count the trivial error checks")
+	fs.BoolVar(&halstead, "halstead", false, "calculate the Halstead metrics and the maintainability index")
+	fs.StringVar(&group, "group", "", "show the total complexity of each type or package")
+	fs.IntVar(&budgetPackage, "budget-package", 0, "show the packages which total complexity > N too")
+	fs.IntVar(&budgetFile, "budget-file", 0, "show the files which total complexity > N too")
+	fs.IntVar(&budgetDir, "budget-dir", 0, "show the directories which total complexity > N too")
+	fs.StringVar(&configFile, "config", "", "the JSON configuration file")
+	fs.StringVar(&includeFuncExpr, "include-func", "", "show the functions which name matches the regexp only")
+	fs.StringVar(&excludeFuncExpr, "exclude-func", "", "do not show the functions which name matches the regexp")
+	fs.StringVar(&pkgExpr, "pkg", "", "show the packages which name matches the regexp only")
+	fs.BoolVar(&exportedOnly, "exported-only", false, "show the exported functions and methods only")
+	fs.Var(&excludes, "exclude", "skip the files and directories matching the glob pattern")
+	fs.BoolVar(&strict, "strict", false, "stop at the first file which can not be parsed")
+	fs.BoolVar(&readStdin, "stdin", false, "read the Go source from the standard input")
+	fs.StringVar(&stdinFilename, "stdin-filename", defaultStdinFilename, "the file name reported for the standard input")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
 	}
 
-	tmpl, err := template.New("gocognit").Parse(format)
-	if err != nil {
-		log.Fatal(err)
+	paths := fs.Args()
+	if len(paths) == 0 && !readStdin || len(paths) > 0 && readStdin {
+		fs.Usage()
+		return 2
 	}
 
-	stats, err := analyze(args, includeTests, enableDiagnostics)
-	if err != nil {
-		log.Fatal(err)
+	fail := func(err error) int {
+		fmt.Fprintf(stderr, "gocognit: %v\n", err)
+		return 1
 	}
 
-	sort.Sort(byComplexity(stats))
-
-	ignoreRegexp, err := prepareRegexp(ignoreExpr)
-	if err != nil {
-		log.Fatal(err)
+	limits := thresholds{
+		over:          over,
+		cycloOver:     cycloOver,
+		maxNesting:    maxNesting,
+		maxStatements: maxStatements,
+		maxLines:      maxLines,
+		maxParams:     maxParams,
+		maxResults:    maxResults,
+		levels:        gocognit.Levels{Warn: warn, Error: errorLevel},
 	}
 
-	filteredStats := filterStats(stats, ignoreRegexp, top, over)
-
-	var written int
-	if jsonEncode {
-		written, err = writeJSONStats(os.Stdout, filteredStats)
-	} else {
-		written, err = writeTextStats(os.Stdout, filteredStats, tmpl)
+	var cfg config
+	if configFile != "" {
+		var err error
+		if cfg, err = readConfig(configFile); err != nil {
+			return fail(err)
+		}
 	}
 
-	if err != nil {
-		log.Fatal(err)
+	for _, b := range []struct {
+		budget *budget
+		value  int
+	}{
+		{&cfg.Budgets.Package, budgetPackage},
+		{&cfg.Budgets.File, budgetFile},
+		{&cfg.Budgets.Dir, budgetDir},
+	} {
+		if b.value > 0 {
+			b.budget.Default = b.value
+		}
 	}
 
-	if avg {
-		showAverage(stats)
+	if command == checkCommand && !limits.enabled() && !cfg.Budgets.enabled() {
+		fmt.Fprintln(stderr, "gocognit check: no limit is set, e.g. -over 15")
+		return 2
 	}
 
-	if over > 0 && written > 0 {
-		os.Exit(1)
+	if jsonEncode {
+		outputFormat = jsonOutputFormat
 	}
-}
 
-func analyzePath(path string, includeTests bool, includeDiagnostic bool) ([]gocognit.Stat, error) {
-	if isDir(path) {
-		return analyzeDir(path, includeTests, nil, includeDiagnostic)
+	switch outputFormat {
+	case textOutputFormat, jsonOutputFormat, jsonReportOutputFormat, csvOutputFormat, tsvOutputFormat,
+		treemapOutputFormat, treemapCSVOutputFormat, sarifOutputFormat:
+	case htmlOutputFormat, markdownOutputFormat:
+		enableDiagnostics = true
+	default:
+		return fail(fmt.Errorf("unknown output format %q", outputFormat))
 	}
 
-	return analyzeFile(path, nil, includeDiagnostic)
-}
-
-func analyze(paths []string, includeTests bool, includeDiagnostic bool) (stats []gocognit.Stat, err error) {
-	var out []gocognit.Stat
-
-	for _, path := range paths {
-		stats, err := analyzePath(path, includeTests, includeDiagnostic)
-		if err != nil {
-			return nil, err
+	if format == defaultFormat {
+		if halstead {
+			format = halsteadFormat
 		}
 
-		out = append(out, stats...)
+		if limits.levels.Enabled() {
+			format = severityPrefix + format
+		}
 	}
 
-	return out, nil
-}
-
-func isDir(filename string) bool {
-	fi, err := os.Stat(filename)
+	switch group {
+	case "":
+	case typeGroup, packageGroup:
+		if outputFormat != textOutputFormat && outputFormat != jsonOutputFormat {
+			return fail(errors.New("-group supports text and json output formats only"))
+		}
+	default:
+		return fail(fmt.Errorf("unknown group %q", group))
+	}
 
-	return err == nil && fi.IsDir()
-}
+	if cfg.Budgets.enabled() {
+		if group != "" {
+			return fail(errors.New("-group can not be used with the budgets"))
+		}
 
-func analyzeFile(fname string, stats []gocognit.Stat, includeDiagnostic bool) ([]gocognit.Stat, error) {
-	fset := token.NewFileSet()
+		if outputFormat != textOutputFormat && outputFormat != jsonOutputFormat && outputFormat != jsonReportOutputFormat {
+			return fail(errors.New("the budgets support text, json and json-report output formats only"))
+		}
+	}
 
-	f, err := parser.ParseFile(fset, fname, nil, parser.ParseComments)
+	tmpl, err := template.New("gocognit").Parse(format)
 	if err != nil {
-		return nil, err
+		return fail(err)
 	}
 
-	return gocognit.ComplexityStatsWithDiagnostic(f, fset, stats, includeDiagnostic), nil
-}
-
-func analyzeDir(dirname string, includeTests bool, stats []gocognit.Stat, trace bool) ([]gocognit.Stat, error) {
-	err := filepath.Walk(dirname, func(path string, info os.FileInfo, err error) error {
+	var baseline []gocognit.Stat
+	if baselineFile != "" {
+		baseline, err = readBaseline(baselineFile)
 		if err != nil {
-			return err
+			return fail(err)
 		}
+	}
 
-		if info.IsDir() {
-			return nil
+	ignoreRegexp, err := prepareRegexp(ignoreExpr)
+	if err != nil {
+		return fail(err)
+	}
+
+	filter := gocognit.FuncFilter{ExportedOnly: exportedOnly}
+	for _, f := range []struct {
+		re   **regexp.Regexp
+		expr string
+	}{
+		{&filter.Include, includeFuncExpr},
+		{&filter.Exclude, excludeFuncExpr},
+		{&filter.Pkg, pkgExpr},
+	} {
+		if *f.re, err = prepareRegexp(f.expr); err != nil {
+			return fail(err)
 		}
+	}
 
-		if !strings.HasSuffix(path, ".go") {
-			return nil
+	opts := gocognit.Options{
+		ScanOptions: gocognit.ScanOptions{
+			Diagnostics: enableDiagnostics || annotate,
+			Rules:       &rules,
+
+			DiscountErrorChecks: goIdiom,
+			Halstead:            halstead,
+			Filter:              filter,
+			Levels:              limits.levels,
+		},
+		IncludeTests: includeTests,
+		Ignore:       ignoreRegexp,
+		Exclude:      excludes,
+		Strict:       strict,
+	}
+
+	// the exclude and score stages
+	var (
+		stats []gocognit.Stat
+		src   sources
+	)
+	if readStdin {
+		// the buffer is kept for the formats showing the source
+		var b []byte
+		if b, err = io.ReadAll(stdin); err != nil {
+			return fail(err)
 		}
 
-		if !includeTests && strings.HasSuffix(path, "_test.go") {
-			return nil
+		src = sources{stdinFilename: b}
+		stats, err = gocognit.AnalyzeSource(stdinFilename, b, opts.ScanOptions)
+	} else {
+		stats, err = gocognit.AnalyzePaths(context.Background(), paths, opts)
+	}
+
+	errs, ok := err.(gocognit.Errors)
+	if ok && !strict {
+		if err := writeErrors(stderr, errs, outputFormat == jsonOutputFormat); err != nil {
+			return fail(err)
 		}
+	} else if err != nil {
+		return fail(err)
+	}
 
-		stats, err = analyzeFile(path, stats, trace)
-		if err != nil {
-			return err
+	// the threshold and top stages
+	p := newPipeline(stats, limits, top)
+
+	var exceeded bool
+	switch {
+	case cfg.Budgets.enabled():
+		// the functions are only shown when their limits are set too
+		var shown []gocognit.Stat
+		if limits.enabled() {
+			shown = p.shown
+			exceeded = len(p.failed) > 0
 		}
 
-		return nil
-	})
+		results := checkBudgets(p.scored, cfg.Budgets)
+		exceeded = exceeded || len(results) > 0
+		err = writeBudgetResults(stdout, shown, results, errs, tmpl, outputFormat != textOutputFormat)
+	case group != "":
+		groups := groupStats(p.scored, group, top, over)
+		exceeded = over > 0 && len(groups) > 0
+		_, err = writeGroupStats(stdout, groups, outputFormat == jsonOutputFormat)
+	default:
+		exceeded = len(p.failed) > 0
+		err = writeStats(stdout, p, errs, outputFormat, tmpl, annotate, enableDiagnostics, baseline, src)
+	}
 
 	if err != nil {
-		return nil, err
+		return fail(err)
 	}
 
-	return stats, nil
+	if avg {
+		showAverage(stdout, p.scored)
+	}
+
+	if exceeded && command != reportCommand {
+		return 1
+	}
+
+	return 0
+}
+
+// newFlagSet returns the flag set of the command, the empty command is the
+// command without a name.
+func newFlagSet(command string, stderr io.Writer) *flag.FlagSet {
+	name := "gocognit"
+	if command != "" {
+		name += " " + command
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(stderr, usageDoc)
+	}
+
+	return fs
+}
+
+func writeStats(w io.Writer, p pipeline, errs gocognit.Errors, outputFormat string, tmpl *template.Template, annotate, enableDiagnostics bool, baseline []gocognit.Stat, src sources) error {
+	var err error
+	switch {
+	case annotate:
+		_, err = writeAnnotatedStats(w, p.shown, src)
+	case outputFormat == jsonOutputFormat:
+		_, err = writeJSONStats(w, p.shown)
+	case outputFormat == jsonReportOutputFormat:
+		_, err = writeJSONReport(w, p.shown, errs)
+	case outputFormat == csvOutputFormat:
+		_, err = writeCSVStats(w, p.shown, ',', enableDiagnostics)
+	case outputFormat == tsvOutputFormat:
+		_, err = writeCSVStats(w, p.shown, '\t', enableDiagnostics)
+	case outputFormat == markdownOutputFormat:
+		_, err = writeMarkdownStats(w, p.scored, p.shown, baseline)
+	case outputFormat == htmlOutputFormat:
+		_, err = writeHTMLStats(w, p.shown, src)
+	case outputFormat == treemapOutputFormat:
+		_, err = writeTreemapJSON(w, p.shown)
+	case outputFormat == treemapCSVOutputFormat:
+		_, err = writeTreemapCSV(w, p.shown)
+	case outputFormat == sarifOutputFormat:
+		_, err = writeSARIFStats(w, p)
+	default:
+		_, err = writeTextStats(w, p.shown, tmpl)
+	}
+
+	return err
 }
 
 func writeTextStats(w io.Writer, stats []gocognit.Stat, tmpl *template.Template) (int, error) {
@@ -289,7 +728,26 @@ func writeTextStats(w io.Writer, stats []gocognit.Stat, tmpl *template.Template)
 	return len(stats), nil
 }
 
+// jsonReport is the document written by -format json-report, and by the
+// budgets encoded as JSON.
+type jsonReport struct {
+	Stats   []gocognit.Stat
+	Budgets []budgetResult `json:",omitempty"` // the groups exceeding their budgets
+	Errors  []fileErrors   // the files analyzed partially
+}
+
+// fileErrors is the errors of a file.
+type fileErrors struct {
+	Filename string
+	Errors   []gocognit.FileError
+}
+
 func writeJSONStats(w io.Writer, stats []gocognit.Stat) (int, error) {
+	if stats == nil {
+		// encode as empty array rather than null
+		stats = []gocognit.Stat{}
+	}
+
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "    ")
 	if err := enc.Encode(stats); err != nil {
@@ -299,55 +757,88 @@ func writeJSONStats(w io.Writer, stats []gocognit.Stat) (int, error) {
 	return len(stats), nil
 }
 
-func prepareRegexp(expr string) (*regexp.Regexp, error) {
-	if expr == "" {
-		return nil, nil
+func writeJSONReport(w io.Writer, stats []gocognit.Stat, errs gocognit.Errors) (int, error) {
+	if err := encodeJSONReport(w, newJSONReport(stats, errs)); err != nil {
+		return 0, err
 	}
 
-	return regexp.Compile(expr)
+	return len(stats), nil
 }
 
-func filterStats(sortedStats []gocognit.Stat, ignoreRegexp *regexp.Regexp, top, over int) []gocognit.Stat {
-	var filtered []gocognit.Stat
+func newJSONReport(stats []gocognit.Stat, errs gocognit.Errors) jsonReport {
+	out := jsonReport{
+		// encode as empty arrays rather than null
+		Stats:  []gocognit.Stat{},
+		Errors: groupErrors(errs),
+	}
+	out.Stats = append(out.Stats, stats...)
 
-	i := 0
-	for _, stat := range sortedStats {
-		if i == top {
-			break
-		}
+	return out
+}
 
-		if stat.Complexity <= over {
-			break
-		}
+func encodeJSONReport(w io.Writer, out jsonReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(out)
+}
 
-		if ignoreRegexp != nil && ignoreRegexp.MatchString(stat.Pos.Filename) {
-			continue
+// groupErrors groups the errors by file in the order of the first error
+// of each file.
+func groupErrors(errs gocognit.Errors) []fileErrors {
+	groups := []fileErrors{}
+	index := make(map[string]int)
+	for _, e := range errs {
+		i, ok := index[e.Pos.Filename]
+		if !ok {
+			i = len(groups)
+			index[e.Pos.Filename] = i
+			groups = append(groups, fileErrors{Filename: e.Pos.Filename})
 		}
 
-		filtered = append(filtered, stat)
-		i++
+		groups[i].Errors = append(groups[i].Errors, e)
 	}
 
-	return filtered
+	return groups
+}
+
+// stringsFlag is a flag which can be repeated.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
 }
 
-func showAverage(stats []gocognit.Stat) {
-	fmt.Printf("Average: %.3g\n", average(stats))
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
 }
 
-func average(stats []gocognit.Stat) float64 {
-	total := 0
-	for _, s := range stats {
-		total += s.Complexity
+// writeErrors reports the errors of the files which are not analyzed
+// completely.
+func writeErrors(w io.Writer, errs gocognit.Errors, jsonEncode bool) error {
+	if jsonEncode {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(errs)
+	}
+
+	for _, e := range errs {
+		if _, err := fmt.Fprintf(w, "gocognit: %s\n", e); err != nil {
+			return err
+		}
 	}
 
-	return float64(total) / float64(len(stats))
+	return nil
 }
 
-type byComplexity []gocognit.Stat
+func prepareRegexp(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+
+	return regexp.Compile(expr)
+}
 
-func (s byComplexity) Len() int      { return len(s) }
-func (s byComplexity) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
-func (s byComplexity) Less(i, j int) bool {
-	return s[i].Complexity >= s[j].Complexity
+func showAverage(w io.Writer, stats []gocognit.Stat) {
+	fmt.Fprintf(w, "Average: %.3g\n", average(stats))
 }
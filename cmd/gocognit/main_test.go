@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/uudashr/gocognit"
+)
+
+var (
+	dirD = filepath.Join("..", "..", "testdata", "src", "d")
+	dirI = filepath.Join("..", "..", "testdata", "src", "i")
+)
+
+const testFormat = "{{.Complexity}} {{.FuncName}}"
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantOut  string
+	}{
+		{
+			name:    "all",
+			args:    []string{"-f", testFormat, dirI, dirD},
+			wantOut: "20 ToRegexp\n7 SumOfPrimes\n2 Logical\n1 GetWords\n1 Receive\n1 isSlash\n",
+		},
+		{
+			name:    "top",
+			args:    []string{"-f", testFormat, "-top", "2", dirI, dirD},
+			wantOut: "20 ToRegexp\n7 SumOfPrimes\n",
+		},
+		{
+			name:    "ignored functions do not consume top slots",
+			args:    []string{"-f", testFormat, "-top", "2", "-ignore", "d.go$", dirI, dirD},
+			wantOut: "7 SumOfPrimes\n2 Logical\n",
+		},
+		{
+			name:     "over",
+			args:     []string{"-f", testFormat, "-over", "5", dirI, dirD},
+			wantCode: 1,
+			wantOut:  "20 ToRegexp\n7 SumOfPrimes\n",
+		},
+		{
+			name:    "over not exceeded",
+			args:    []string{"-f", testFormat, "-over", "20", dirI, dirD},
+			wantOut: "",
+		},
+		{
+			name:     "top after over",
+			args:     []string{"-f", testFormat, "-top", "1", "-over", "5", dirI, dirD},
+			wantCode: 1,
+			wantOut:  "20 ToRegexp\n",
+		},
+		{
+			name:     "flag order does not matter",
+			args:     []string{"-f", testFormat, "-over", "5", "-top", "1", dirI, dirD},
+			wantCode: 1,
+			wantOut:  "20 ToRegexp\n",
+		},
+		{
+			name:    "average of all scored functions",
+			args:    []string{"-f", testFormat, "-top", "0", "-avg", dirI, dirD},
+			wantOut: "Average: 5.33\n",
+		},
+		{
+			name:     "average is not affected by over and top",
+			args:     []string{"-f", testFormat, "-over", "5", "-top", "1", "-avg", dirI, dirD},
+			wantCode: 1,
+			wantOut:  "20 ToRegexp\nAverage: 5.33\n",
+		},
+		{
+			name:    "average without ignored files",
+			args:    []string{"-f", testFormat, "-top", "0", "-avg", "-ignore", "d.go$", dirI, dirD},
+			wantOut: "Average: 2.75\n",
+		},
+		{
+			name:     "exit code when the exceeded functions are not shown",
+			args:     []string{"-f", testFormat, "-over", "5", "-top", "0", dirI, dirD},
+			wantCode: 1,
+			wantOut:  "",
+		},
+		{
+			name:     "check",
+			args:     []string{"check", "-f", testFormat, "-over", "5", dirI, dirD},
+			wantCode: 1,
+			wantOut:  "20 ToRegexp\n7 SumOfPrimes\n",
+		},
+		{
+			name:    "report does not fail",
+			args:    []string{"report", "-f", testFormat, "-over", "5", dirI, dirD},
+			wantOut: "20 ToRegexp\n7 SumOfPrimes\n",
+		},
+		{
+			name:    "group",
+			args:    []string{"-group", "package", "-ignore", "d.go$", dirI},
+			wantOut: "11 testdata " + dirI + " funcs=4 max=7 avg=2.75\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(""), &stdout, &stderr)
+
+			if code != tt.wantCode {
+				t.Errorf("got exit code %d, want %d (stderr: %q)", code, tt.wantCode, stderr.String())
+			}
+
+			if got := stdout.String(); got != tt.wantOut {
+				t.Errorf("got output %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantErr  string
+	}{
+		{"no paths", nil, 2, "Usage:"},
+		{"unknown flag", []string{"-unknown", dirD}, 2, "flag provided but not defined"},
+		{"unknown format", []string{"-format", "xml", dirD}, 1, `unknown output format "xml"`},
+		{"invalid regexp", []string{"-ignore", "(", dirD}, 1, "error parsing regexp"},
+		{"check without limit", []string{"check", dirD}, 2, "no limit is set"},
+		{"explain without function", []string{"explain", dirD}, 1, "not in the form of <file>:<function>"},
+		{"diff without runs", []string{"diff", "old.json"}, 2, "Usage:"},
+		{"missing path", []string{filepath.Join(dirD, "missing.go")}, 1, "missing.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(""), &stdout, &stderr)
+
+			if code != tt.wantCode {
+				t.Errorf("got exit code %d, want %d", code, tt.wantCode)
+			}
+
+			if !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("got stderr %q, want it contains %q", stderr.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRun_History(t *testing.T) {
+	defer func(orig func() time.Time) { now = orig }(now)
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "p.go")
+	file := filepath.Join(dir, "history.json")
+
+	for i, body := range []string{
+		"if ok { return 1 }\n\treturn 0",
+		"if ok { if !ok { return 2 } }\n\treturn 0",
+	} {
+		now = func() time.Time { return time.Date(2026, 10, 1+i, 9, 0, 0, 0, time.UTC) }
+
+		code := "package p\n\nfunc F(ok bool) int {\n\t" + body + "\n}\n"
+		if err := os.WriteFile(src, []byte(code), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"history", "record", "-file", file, src}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+			t.Fatalf("got exit code %d, want 0 (stderr: %q)", code, stderr.String())
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"history", "show", "-file", file}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("got exit code %d, want 0 (stderr: %q)", code, stderr.String())
+	}
+
+	for _, want := range []string{
+		"2026-10-01T09:00:00Z - funcs=1 complexity=1\n",
+		"2026-10-02T09:00:00Z - funcs=1 complexity=3 (+2)\n",
+		"+2 p F " + src + " (1 -> 3)\n",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("got output %q, want it contains %q", stdout.String(), want)
+		}
+	}
+}
+
+func TestGroupErrors(t *testing.T) {
+	errs := gocognit.Errors{
+		{Pos: token.Position{Filename: "b.go", Line: 1}, Msg: "b1"},
+		{Pos: token.Position{Filename: "a.go", Line: 2}, Msg: "a1"},
+		{Pos: token.Position{Filename: "b.go", Line: 3}, Msg: "b2"},
+	}
+
+	var got []string
+	for _, g := range groupErrors(errs) {
+		for _, e := range g.Errors {
+			got = append(got, g.Filename+" "+e.Msg)
+		}
+	}
+
+	want := "b.go b1,b.go b2,a.go a1"
+	if strings.Join(got, ",") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, ","), want)
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/uudashr/gocognit"
+)
+
+const (
+	// markdownMaxSize keeps the summary below the size limit of a pull
+	// request comment.
+	markdownMaxSize = 60000
+
+	// markdownMaxRows is the maximum number of rows of each table.
+	markdownMaxRows = 20
+)
+
+type markdownPackage struct {
+	Name  string
+	Dir   string
+	Funcs int
+	Total int
+	Base  int
+}
+
+// writeMarkdownStats writes a summary of the stats as markdown. The totals
+// are calculated from all the stats and the top offenders are the shown
+// stats. The deltas are only written when the baseline is not nil.
+func writeMarkdownStats(w io.Writer, stats, shown, baseline []gocognit.Stat) (int, error) {
+	shown = sortedForMarkdown(shown)
+	if len(shown) > markdownMaxRows {
+		shown = shown[:markdownMaxRows]
+	}
+
+	var base map[string]int
+	if baseline != nil {
+		base = baselineComplexities(baseline)
+	}
+
+	var buf bytes.Buffer
+
+	total, baseTotal := 0, 0
+	for _, stat := range stats {
+		total += stat.Complexity
+	}
+
+	for _, stat := range baseline {
+		baseTotal += stat.Complexity
+	}
+
+	fmt.Fprintf(&buf, "## Cognitive complexity\n\n")
+	fmt.Fprintf(&buf, "%d functions, total complexity %d", len(stats), total)
+	if baseline != nil {
+		fmt.Fprintf(&buf, " (%s)", formatDelta(total-baseTotal))
+	}
+	fmt.Fprintf(&buf, ", average %.3g\n\n", average(stats))
+
+	if len(shown) > 0 {
+		writeMarkdownOffenders(&buf, shown, base)
+	}
+
+	pkgs := markdownPackages(stats, baseline)
+	if len(pkgs) > 0 {
+		writeMarkdownPackages(&buf, pkgs, baseline != nil)
+	}
+
+	var omitted int
+	for _, stat := range shown {
+		var details bytes.Buffer
+		writeMarkdownDetails(&details, stat)
+
+		if buf.Len()+details.Len() > markdownMaxSize {
+			omitted++
+			continue
+		}
+
+		buf.Write(details.Bytes())
+	}
+
+	if omitted > 0 {
+		fmt.Fprintf(&buf, "\n_The increments of %d functions are omitted to fit the size limit._\n", omitted)
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return len(shown), nil
+}
+
+func writeMarkdownOffenders(buf *bytes.Buffer, shown []gocognit.Stat, base map[string]int) {
+	fmt.Fprintf(buf, "### Top offenders\n\n")
+	if base != nil {
+		fmt.Fprintf(buf, "| Complexity | Delta | Cyclomatic | Function | Package | Position |\n")
+		fmt.Fprintf(buf, "|---:|---:|---:|---|---|---|\n")
+	} else {
+		fmt.Fprintf(buf, "| Complexity | Cyclomatic | Function | Package | Position |\n")
+		fmt.Fprintf(buf, "|---:|---:|---|---|---|\n")
+	}
+
+	for _, stat := range shown {
+		fmt.Fprintf(buf, "| %d ", stat.Complexity)
+		if base != nil {
+			if prev, ok := base[statKey(stat)]; ok {
+				fmt.Fprintf(buf, "| %s ", formatDelta(stat.Complexity-prev))
+			} else {
+				fmt.Fprintf(buf, "| new ")
+			}
+		}
+
+		fmt.Fprintf(buf, "| %d | %s | %s | %s |\n", stat.Cyclomatic,
+			markdownCode(stat.FuncName), markdownEscape(stat.PkgName), markdownEscape(stat.Pos.String()))
+	}
+
+	fmt.Fprintln(buf)
+}
+
+func writeMarkdownPackages(buf *bytes.Buffer, pkgs []markdownPackage, withDelta bool) {
+	fmt.Fprintf(buf, "### Packages\n\n")
+	if withDelta {
+		fmt.Fprintf(buf, "| Package | Directory | Functions | Total | Delta |\n")
+		fmt.Fprintf(buf, "|---|---|---:|---:|---:|\n")
+	} else {
+		fmt.Fprintf(buf, "| Package | Directory | Functions | Total |\n")
+		fmt.Fprintf(buf, "|---|---|---:|---:|\n")
+	}
+
+	rows := pkgs
+	if len(rows) > markdownMaxRows {
+		rows = rows[:markdownMaxRows]
+	}
+
+	for _, pkg := range rows {
+		fmt.Fprintf(buf, "| %s | %s | %d | %d ", markdownEscape(pkg.Name), markdownEscape(pkg.Dir), pkg.Funcs, pkg.Total)
+		if withDelta {
+			fmt.Fprintf(buf, "| %s ", formatDelta(pkg.Total-pkg.Base))
+		}
+		fmt.Fprintf(buf, "|\n")
+	}
+
+	if n := len(pkgs) - len(rows); n > 0 {
+		fmt.Fprintf(buf, "\n_%d more packages are omitted._\n", n)
+	}
+
+	fmt.Fprintln(buf)
+}
+
+func writeMarkdownDetails(buf *bytes.Buffer, stat gocognit.Stat) {
+	fmt.Fprintf(buf, "<details>\n<summary><code>%s</code> %d at %s</summary>\n\n",
+		htmlEscaper.Replace(stat.FuncName), stat.Complexity, htmlEscaper.Replace(stat.Pos.String()))
+	for _, diag := range stat.Diagnostics {
+		fmt.Fprintf(buf, "- line %d: `%s %s`\n", diag.Pos.Line, diag, diag.Text)
+	}
+	fmt.Fprintf(buf, "\n</details>\n\n")
+}
+
+// markdownPackages returns the packages sorted by the total complexity
+// with the totals of the baseline.
+func markdownPackages(stats, baseline []gocognit.Stat) []markdownPackage {
+	index := make(map[string]int)
+
+	var pkgs []markdownPackage
+	add := func(stat gocognit.Stat, current bool) {
+		key := pkgKey(stat)
+		i, ok := index[key]
+		if !ok {
+			i = len(pkgs)
+			index[key] = i
+			pkgs = append(pkgs, markdownPackage{
+				Name: stat.PkgName,
+				Dir:  filepath.Dir(stat.Pos.Filename),
+			})
+		}
+
+		if current {
+			pkgs[i].Funcs++
+			pkgs[i].Total += stat.Complexity
+		} else {
+			pkgs[i].Base += stat.Complexity
+		}
+	}
+
+	for _, stat := range stats {
+		add(stat, true)
+	}
+
+	for _, stat := range baseline {
+		add(stat, false)
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].Total != pkgs[j].Total {
+			return pkgs[i].Total > pkgs[j].Total
+		}
+
+		if pkgs[i].Dir != pkgs[j].Dir {
+			return pkgs[i].Dir < pkgs[j].Dir
+		}
+
+		return pkgs[i].Name < pkgs[j].Name
+	})
+
+	return pkgs
+}
+
+// sortedForMarkdown returns a copy of the stats sorted by the complexity,
+// ties are broken by the position so the output is deterministic.
+func sortedForMarkdown(stats []gocognit.Stat) []gocognit.Stat {
+	out := append([]gocognit.Stat(nil), stats...)
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Complexity != b.Complexity {
+			return a.Complexity > b.Complexity
+		}
+
+		if a.Pos.Filename != b.Pos.Filename {
+			return a.Pos.Filename < b.Pos.Filename
+		}
+
+		return a.Pos.Offset < b.Pos.Offset
+	})
+
+	return out
+}
+
+func formatDelta(d int) string {
+	if d > 0 {
+		return fmt.Sprintf("+%d", d)
+	}
+
+	return fmt.Sprintf("%d", d)
+}
+
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+func markdownCode(s string) string {
+	return "`" + markdownEscape(s) + "`"
+}
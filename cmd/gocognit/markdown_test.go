@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/uudashr/gocognit"
+)
+
+var markdownStats = []gocognit.Stat{
+	{
+		PkgName:    "p",
+		FuncName:   "Sum",
+		Complexity: 3,
+		Cyclomatic: 3,
+		Pos:        token.Position{Filename: "p/p.go", Line: 3, Column: 1},
+		Diagnostics: []gocognit.Diagnostic{
+			{Inc: 1, Text: "for", Pos: gocognit.DiagnosticPosition{Offset: 40, Line: 4, Column: 2}},
+			{Inc: 2, Nesting: 1, Text: "if", Pos: gocognit.DiagnosticPosition{Offset: 60, Line: 5, Column: 3}},
+		},
+	},
+	{
+		PkgName:    "p",
+		FuncName:   "Or",
+		Complexity: 1,
+		Cyclomatic: 2,
+		Pos:        token.Position{Filename: "p/p.go", Line: 12, Column: 1},
+		Diagnostics: []gocognit.Diagnostic{
+			{Inc: 1, Text: "||", Pos: gocognit.DiagnosticPosition{Offset: 140, Line: 13, Column: 11}},
+		},
+	},
+}
+
+func TestWriteMarkdownStats(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := writeMarkdownStats(&buf, markdownStats, markdownStats[:1], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 1 {
+		t.Errorf("got %d rows, want 1", n)
+	}
+
+	want := "## Cognitive complexity\n\n" +
+		"2 functions, total complexity 4, average 2\n\n" +
+		"### Top offenders\n\n" +
+		"| Complexity | Cyclomatic | Function | Package | Position |\n" +
+		"|---:|---:|---|---|---|\n" +
+		"| 3 | 3 | `Sum` | p | p/p.go:3:1 |\n\n" +
+		"### Packages\n\n" +
+		"| Package | Directory | Functions | Total |\n" +
+		"|---|---|---:|---:|\n" +
+		"| p | p | 2 | 4 |\n\n" +
+		"<details>\n<summary><code>Sum</code> 3 at p/p.go:3:1</summary>\n\n" +
+		"- line 4: `+1 for`\n" +
+		"- line 5: `+2 (nesting=1) if`\n" +
+		"\n</details>\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteMarkdownStats_Baseline(t *testing.T) {
+	baseline := []gocognit.Stat{
+		{PkgName: "p", FuncName: "Sum", Complexity: 1, Pos: token.Position{Filename: "p/p.go", Line: 3}},
+	}
+
+	var buf bytes.Buffer
+	if _, err := writeMarkdownStats(&buf, markdownStats, markdownStats, baseline); err != nil {
+		t.Fatal(err)
+	}
+
+	got := buf.String()
+	for _, want := range []string{
+		"2 functions, total complexity 4 (+3), average 2\n",
+		"| Complexity | Delta | Cyclomatic | Function | Package | Position |\n",
+		"| 3 | +2 | 3 | `Sum` | p | p/p.go:3:1 |\n",
+		"| 1 | new | 2 | `Or` | p | p/p.go:12:1 |\n",
+		"| p | p | 2 | 4 | +3 |\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteMarkdownStats_SizeLimit(t *testing.T) {
+	diags := make([]gocognit.Diagnostic, 2000)
+	for i := range diags {
+		diags[i] = gocognit.Diagnostic{Inc: 1, Text: "if", Pos: gocognit.DiagnosticPosition{Line: i + 1}}
+	}
+
+	stats := []gocognit.Stat{
+		{PkgName: "p", FuncName: "Big", Complexity: 2000, Diagnostics: diags, Pos: token.Position{Filename: "p/p.go", Line: 1}},
+		{PkgName: "p", FuncName: "Large", Complexity: 2000, Diagnostics: diags, Pos: token.Position{Filename: "p/p.go", Line: 2}},
+		{PkgName: "p", FuncName: "Small", Complexity: 1, Diagnostics: diags[:1], Pos: token.Position{Filename: "p/p.go", Line: 3}},
+	}
+
+	var buf bytes.Buffer
+	if _, err := writeMarkdownStats(&buf, stats, stats, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	got := buf.String()
+	if buf.Len() > markdownMaxSize {
+		t.Errorf("got %d bytes, want at most %d", buf.Len(), markdownMaxSize)
+	}
+
+	if !strings.Contains(got, "<summary><code>Small</code>") {
+		t.Errorf("the details of Small are omitted, want them to fit")
+	}
+
+	if want := "_The increments of 1 functions are omitted to fit the size limit._"; !strings.Contains(got, want) {
+		t.Errorf("missing %q in:\n%s", want, got)
+	}
+}
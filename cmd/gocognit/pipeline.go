@@ -0,0 +1,160 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/uudashr/gocognit"
+)
+
+// pipeline is the result of the stages the stats go through:
+//
+//  1. exclude: the files and functions are left out before they are
+//     scored, by -test, -ignore, -exclude and the function filters.
+//  2. score: the remaining functions are analyzed and sorted by their
+//     complexity, the average and the totals are calculated from them.
+//  3. threshold: the functions exceeding any of the limits are kept, the
+//     exit code is 1 when any of them exceeds a limit other than -warn.
+//  4. top: the N most complex of them are shown.
+//
+// The first two stages are done by the analysis.
+type pipeline struct {
+	scored   []gocognit.Stat
+	exceeded []gocognit.Stat
+	failed   []gocognit.Stat // exceeding the hard limits
+	shown    []gocognit.Stat
+}
+
+func newPipeline(scored []gocognit.Stat, limits thresholds, top int) pipeline {
+	sort.Stable(byComplexity(scored))
+
+	p := pipeline{scored: scored}
+	for _, stat := range scored {
+		if limits.exceeded(stat) {
+			p.exceeded = append(p.exceeded, stat)
+		}
+
+		if limits.failed(stat) {
+			p.failed = append(p.failed, stat)
+		}
+	}
+
+	p.shown = p.exceeded
+	if top >= 0 && top < len(p.shown) {
+		p.shown = p.shown[:top]
+	}
+
+	return p
+}
+
+// thresholds are the limits of the metrics, a function is shown when it
+// exceeds any of the enabled limits.
+type thresholds struct {
+	over      int // cognitive complexity
+	cycloOver int // cyclomatic complexity, 0 disables it
+
+	// the other function metrics, 0 disables them
+	maxNesting    int
+	maxStatements int
+	maxLines      int
+	maxParams     int
+	maxResults    int
+
+	// the soft and the hard limits of the cognitive complexity, -warn and
+	// -error
+	levels gocognit.Levels
+}
+
+// limit is a metric value with its limit.
+type limit struct {
+	value, max int
+}
+
+// others returns the limits other than the cognitive complexity.
+func (t thresholds) others(stat gocognit.Stat) []limit {
+	return []limit{
+		{stat.Cyclomatic, t.cycloOver},
+		{stat.MaxNesting, t.maxNesting},
+		{stat.Statements, t.maxStatements},
+		{stat.Lines, t.maxLines},
+		{stat.Params, t.maxParams},
+		{stat.Results, t.maxResults},
+	}
+}
+
+// enabled reports whether any limit is set, then the functions exceeding
+// them are shown.
+func (t thresholds) enabled() bool {
+	return t.over > 0 || t.othersEnabled()
+}
+
+func (t thresholds) othersEnabled() bool {
+	if t.levels.Enabled() {
+		return true
+	}
+
+	for _, l := range t.others(gocognit.Stat{}) {
+		if l.max > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t thresholds) exceeded(stat gocognit.Stat) bool {
+	for _, l := range t.others(stat) {
+		if l.max > 0 && l.value > l.max {
+			return true
+		}
+	}
+
+	if t.levels.Severity(stat.Complexity) != "" {
+		return true
+	}
+
+	// the cognitive complexity is the default metric, it is only left out
+	// when another limit is set alone
+	if t.over > 0 || !t.othersEnabled() {
+		return stat.Complexity > t.over
+	}
+
+	return false
+}
+
+// failed reports whether the stat exceeds a hard limit, all of them are
+// hard limits except -warn.
+func (t thresholds) failed(stat gocognit.Stat) bool {
+	for _, l := range t.others(stat) {
+		if l.max > 0 && l.value > l.max {
+			return true
+		}
+	}
+
+	if t.over > 0 && stat.Complexity > t.over {
+		return true
+	}
+
+	return t.levels.Severity(stat.Complexity) == gocognit.SeverityError
+}
+
+// average returns the average complexity, 0 when there are no functions.
+func average(stats []gocognit.Stat) float64 {
+	if len(stats) == 0 {
+		return 0
+	}
+
+	total := 0
+	for _, s := range stats {
+		total += s.Complexity
+	}
+
+	return float64(total) / float64(len(stats))
+}
+
+type byComplexity []gocognit.Stat
+
+func (s byComplexity) Len() int      { return len(s) }
+func (s byComplexity) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byComplexity) Less(i, j int) bool {
+	return s[i].Complexity > s[j].Complexity
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/uudashr/gocognit"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifRuleID  = "cognitive-complexity"
+)
+
+// The subset of SARIF used by the code scanning tools.
+type (
+	sarifLog struct {
+		Version string     `json:"version"`
+		Schema  string     `json:"$schema"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+		EndLine     int `json:"endLine,omitempty"`
+		EndColumn   int `json:"endColumn,omitempty"`
+	}
+)
+
+// writeSARIFStats writes the shown stats as SARIF results. The level is
+// the severity of the stat, the stats exceeding the other hard limits are
+// errors and the rest are notes.
+func writeSARIFStats(w io.Writer, p pipeline) (int, error) {
+	failed := make(map[string]bool, len(p.failed))
+	for _, stat := range p.failed {
+		failed[stat.Pos.String()] = true
+	}
+
+	results := make([]sarifResult, 0, len(p.shown))
+	for _, stat := range p.shown {
+		level := string(stat.Severity)
+		if level == "" {
+			level = "note"
+			if failed[stat.Pos.String()] {
+				level = string(gocognit.SeverityError)
+			}
+		}
+
+		results = append(results, sarifResult{
+			RuleID:  sarifRuleID,
+			Level:   level,
+			Message: sarifMessage{Text: fmt.Sprintf("cognitive complexity %d of func %s", stat.Complexity, stat.FuncName)},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(stat.Pos.Filename)},
+					Region: sarifRegion{
+						StartLine:   stat.Pos.Line,
+						StartColumn: stat.Pos.Column,
+						EndLine:     stat.End.Line,
+						EndColumn:   stat.End.Column,
+					},
+				},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "gocognit",
+				InformationURI: "https://github.com/uudashr/gocognit",
+				Rules: []sarifRule{{
+					ID:               sarifRuleID,
+					ShortDescription: sarifMessage{Text: "Cognitive complexity of a function is high"},
+				}},
+			}},
+			Results: results,
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(log); err != nil {
+		return 0, err
+	}
+
+	return len(results), nil
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/txtar"
+)
+
+var update = flag.Bool("update", false, "update the stdout and stderr of the scripts")
+
+// TestScripts runs the scenarios of testdata/script/*.txtar. The comment of
+// an archive has the command line, the expected exit code and the shared
+// fixtures the scenario uses:
+//
+//	# description
+//	use stack
+//	gocognit -over 5 .
+//	exit 1
+//
+// The fixtures, the directories of testdata/fixture, and the files of the
+// archive are written to a temporary directory, the command runs there.
+// The "stdin" file is the standard input, the "stdout" and "stderr" files
+// are the expected outputs, missing means empty. Run the tests with -update
+// flag to rewrite them.
+func TestScripts(t *testing.T) {
+	scripts, err := filepath.Glob(filepath.Join("testdata", "script", "*.txtar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(scripts) == 0 {
+		t.Fatal("no scripts found")
+	}
+
+	for _, script := range scripts {
+		script := script
+		t.Run(strings.TrimSuffix(filepath.Base(script), ".txtar"), func(t *testing.T) {
+			runScript(t, script)
+		})
+	}
+}
+
+func runScript(t *testing.T, script string) {
+	ar, err := txtar.ParseFile(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := parseScriptComment(string(ar.Comment))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	for _, name := range h.fixtures {
+		copyFixture(t, filepath.Join("testdata", "fixture", name), dir)
+	}
+
+	var stdin, wantStdout, wantStderr []byte
+	for _, f := range ar.Files {
+		switch f.Name {
+		case "stdin":
+			stdin = f.Data
+		case "stdout":
+			wantStdout = f.Data
+		case "stderr":
+			wantStderr = f.Data
+		default:
+			path := filepath.Join(dir, filepath.FromSlash(f.Name))
+			if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := os.WriteFile(path, f.Data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := inDir(t, dir, func() int {
+		return run(h.args, bytes.NewReader(stdin), &stdout, &stderr)
+	})
+
+	if *update {
+		updateScript(t, script, ar, stdout.Bytes(), stderr.Bytes())
+		return
+	}
+
+	if code != h.code {
+		t.Errorf("got exit code %d, want %d (stderr: %q)", code, h.code, stderr.String())
+	}
+
+	if got := stdout.String(); got != string(wantStdout) {
+		t.Errorf("got stdout:\n%s\nwant:\n%s", got, wantStdout)
+	}
+
+	if got := stderr.String(); got != string(wantStderr) {
+		t.Errorf("got stderr:\n%s\nwant:\n%s", got, wantStderr)
+	}
+}
+
+// copyFixture copies the files of the fixture directory to dir.
+func copyFixture(t *testing.T, fixture, dir string) {
+	err := filepath.Walk(fixture, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(fixture, path)
+		if err != nil {
+			return err
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		dst := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+			return err
+		}
+
+		return os.WriteFile(dst, b, 0o600)
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// inDir calls fn in the directory, the working directory is shared by the
+// whole process so the scripts must not run in parallel.
+func inDir(t *testing.T, dir string, fn func() int) int {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	return fn()
+}
+
+func updateScript(t *testing.T, script string, ar *txtar.Archive, stdout, stderr []byte) {
+	var files []txtar.File
+	for _, f := range ar.Files {
+		if f.Name != "stdout" && f.Name != "stderr" {
+			files = append(files, f)
+		}
+	}
+
+	if len(stdout) > 0 {
+		files = append(files, txtar.File{Name: "stdout", Data: stdout})
+	}
+
+	if len(stderr) > 0 {
+		files = append(files, txtar.File{Name: "stderr", Data: stderr})
+	}
+
+	ar.Files = files
+	if err := os.WriteFile(script, txtar.Format(ar), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// scriptHeader is the comment of a script.
+type scriptHeader struct {
+	args     []string // the arguments of the command line
+	code     int      // the expected exit code
+	fixtures []string // the names of the shared fixtures
+}
+
+func parseScriptComment(comment string) (scriptHeader, error) {
+	var (
+		h     scriptHeader
+		found bool
+		err   error
+	)
+
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case line == "gocognit" || strings.HasPrefix(line, "gocognit "):
+			if h.args, err = splitArgs(strings.TrimPrefix(line, "gocognit")); err != nil {
+				return h, err
+			}
+
+			found = true
+		case strings.HasPrefix(line, "exit "):
+			if h.code, err = strconv.Atoi(strings.TrimPrefix(line, "exit ")); err != nil {
+				return h, err
+			}
+		case strings.HasPrefix(line, "use "):
+			h.fixtures = append(h.fixtures, strings.TrimSpace(strings.TrimPrefix(line, "use ")))
+		default:
+			return h, fmt.Errorf("unknown line %q", line)
+		}
+	}
+
+	if !found {
+		return h, fmt.Errorf("no gocognit command line")
+	}
+
+	return h, nil
+}
+
+// splitArgs splits the command line by spaces, the single quoted strings
+// are kept as is.
+func splitArgs(line string) ([]string, error) {
+	var (
+		args   []string
+		arg    strings.Builder
+		inArg  bool
+		quoted bool
+	)
+
+	for _, r := range line {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			inArg = true
+		case r == ' ' && !quoted:
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	return args, nil
+}
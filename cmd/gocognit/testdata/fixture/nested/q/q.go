@@ -0,0 +1,11 @@
+package q
+
+func Both(a, b bool) bool {
+	if a { // +1
+		if b { // +2 (nesting=1)
+			return true
+		}
+	}
+
+	return false
+} // total complexity = 3
@@ -0,0 +1,46 @@
+package p
+
+import "strconv"
+
+// Sum sums the numbers, the invalid ones are skipped.
+func Sum(ss []string) (total int) {
+	for _, s := range ss { // +1
+		n, err := strconv.Atoi(s)
+		if err != nil { // +2 (nesting=1)
+			continue
+		}
+
+		if n > 0 && n < 100 { // +2 (nesting=1), +1 &&
+			total += n
+		}
+	}
+
+	return total
+} // total complexity = 6
+
+type Stack struct {
+	items []int
+}
+
+func (s *Stack) Pop() (int, bool) {
+	if len(s.items) == 0 { // +1
+		return 0, false
+	}
+
+	n := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return n, true
+} // total complexity = 1
+
+func (s Stack) String() string {
+	switch len(s.items) { // +1
+	case 0:
+		return "empty"
+	default:
+		return "stack"
+	}
+} // total complexity = 1
+
+func square(n int) int {
+	return n * n
+} // total complexity = 0
@@ -0,0 +1,9 @@
+package p
+
+import "testing"
+
+func TestSum(t *testing.T) {
+	if Sum([]string{"1"}) != 1 { // +1
+		t.Fail()
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/uudashr/gocognit"
+)
+
+const (
+	treemapDirKind  = "dir"
+	treemapFileKind = "file"
+	treemapFuncKind = "func"
+)
+
+// treemapNode is a node of the dir → file → function hierarchy. The keys
+// follow the d3 hierarchy conventions so it can be used as is.
+type treemapNode struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Kind       string         `json:"kind"`
+	Package    string         `json:"package,omitempty"`
+	Complexity int            `json:"complexity"`
+	Lines      int            `json:"lines"`
+	MaxNesting int            `json:"maxNesting"`
+	Children   []*treemapNode `json:"children,omitempty"`
+
+	parent   *treemapNode
+	childIDs map[string]*treemapNode
+}
+
+func (n *treemapNode) child(id, name, kind string) *treemapNode {
+	if c, ok := n.childIDs[id]; ok {
+		return c
+	}
+
+	c := &treemapNode{
+		ID:     id,
+		Name:   name,
+		Kind:   kind,
+		parent: n,
+	}
+
+	if n.childIDs == nil {
+		n.childIDs = make(map[string]*treemapNode)
+	}
+
+	n.childIDs[id] = c
+	n.Children = append(n.Children, c)
+
+	return c
+}
+
+// add accumulates the function metrics to the node and its ancestors.
+func (n *treemapNode) add(complexity, lines, maxNesting int) {
+	for ; n != nil; n = n.parent {
+		n.Complexity += complexity
+		n.Lines += lines
+
+		if maxNesting > n.MaxNesting {
+			n.MaxNesting = maxNesting
+		}
+	}
+}
+
+func buildTreemap(stats []gocognit.Stat) *treemapNode {
+	root := &treemapNode{
+		ID:   ".",
+		Name: ".",
+		Kind: treemapDirKind,
+	}
+
+	for _, stat := range stats {
+		filename := filepath.ToSlash(stat.Pos.Filename)
+
+		node := root
+		dir := filepath.ToSlash(filepath.Dir(stat.Pos.Filename))
+		if dir != "." {
+			var path string
+			for _, name := range strings.Split(dir, "/") {
+				path += name + "/"
+				if name == "" {
+					continue
+				}
+
+				node = node.child(strings.TrimSuffix(path, "/"), name, treemapDirKind)
+			}
+		}
+
+		node = node.child(filename, filepath.Base(filename), treemapFileKind)
+		node.Package = stat.PkgName
+
+		id := fmt.Sprintf("%s:%d", filename, stat.Pos.Line)
+		node = node.child(id, stat.FuncName, treemapFuncKind)
+		node.Package = stat.PkgName
+		node.add(stat.Complexity, stat.Lines, stat.MaxNesting)
+	}
+
+	return root
+}
+
+func writeTreemapJSON(w io.Writer, stats []gocognit.Stat) (int, error) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(buildTreemap(stats)); err != nil {
+		return 0, err
+	}
+
+	return len(stats), nil
+}
+
+// writeTreemapCSV writes the hierarchy as flat rows with id and parent
+// columns, suitable for d3.stratify or Grafana.
+func writeTreemapCSV(w io.Writer, stats []gocognit.Stat) (int, error) {
+	cw := csv.NewWriter(w)
+
+	header := []string{"id", "parent", "name", "kind", "package", "complexity", "lines", "max_nesting"}
+	if err := cw.Write(header); err != nil {
+		return 0, err
+	}
+
+	var walk func(n *treemapNode) error
+	walk = func(n *treemapNode) error {
+		var parent string
+		if n.parent != nil {
+			parent = n.parent.ID
+		}
+
+		record := []string{
+			n.ID,
+			parent,
+			n.Name,
+			n.Kind,
+			n.Package,
+			strconv.Itoa(n.Complexity),
+			strconv.Itoa(n.Lines),
+			strconv.Itoa(n.MaxNesting),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+
+		for _, c := range n.Children {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(buildTreemap(stats)); err != nil {
+		return 0, err
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return 0, err
+	}
+
+	return len(stats), nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"testing"
+
+	"github.com/uudashr/gocognit"
+)
+
+var treemapStats = []gocognit.Stat{
+	{PkgName: "p", FuncName: "A", Complexity: 3, Lines: 10, MaxNesting: 2, Pos: token.Position{Filename: "p/a.go", Line: 3}},
+	{PkgName: "p", FuncName: "B", Complexity: 1, Lines: 4, MaxNesting: 1, Pos: token.Position{Filename: "p/a.go", Line: 20}},
+	{PkgName: "q", FuncName: "C", Complexity: 5, Lines: 8, MaxNesting: 3, Pos: token.Position{Filename: "p/q/c.go", Line: 5}},
+}
+
+func TestBuildTreemap(t *testing.T) {
+	root := buildTreemap(treemapStats)
+
+	tests := []struct {
+		node       *treemapNode
+		id         string
+		kind       string
+		complexity int
+		lines      int
+		maxNesting int
+	}{
+		{root, ".", treemapDirKind, 9, 22, 3},
+		{root.Children[0], "p", treemapDirKind, 9, 22, 3},
+		{root.Children[0].Children[0], "p/a.go", treemapFileKind, 4, 14, 2},
+		{root.Children[0].Children[0].Children[1], "p/a.go:20", treemapFuncKind, 1, 4, 1},
+		{root.Children[0].Children[1], "p/q", treemapDirKind, 5, 8, 3},
+	}
+
+	for _, tt := range tests {
+		n := tt.node
+		if n.ID != tt.id || n.Kind != tt.kind || n.Complexity != tt.complexity || n.Lines != tt.lines || n.MaxNesting != tt.maxNesting {
+			t.Errorf("got %s %s complexity=%d lines=%d nesting=%d, want %s %s complexity=%d lines=%d nesting=%d",
+				n.ID, n.Kind, n.Complexity, n.Lines, n.MaxNesting,
+				tt.id, tt.kind, tt.complexity, tt.lines, tt.maxNesting)
+		}
+	}
+}
+
+func TestWriteTreemapCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := writeTreemapCSV(&buf, treemapStats); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `id,parent,name,kind,package,complexity,lines,max_nesting
+.,,.,dir,,9,22,3
+p,.,p,dir,,9,22,3
+p/a.go,p,a.go,file,p,4,14,2
+p/a.go:3,p/a.go,A,func,p,3,10,2
+p/a.go:20,p/a.go,B,func,p,1,4,1
+p/q,p,q,dir,,5,8,3
+p/q/c.go,p/q,c.go,file,q,5,8,3
+p/q/c.go:5,p/q/c.go,C,func,q,5,8,3
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+package gocognit
+
+import (
+	"fmt"
+	"go/token"
+)
+
+// FileError is an error of a file, such as a syntax error.
+type FileError struct {
+	Pos token.Position // only the Filename when the position is unknown
+	Msg string
+}
+
+func (e FileError) Error() string {
+	if e.Pos.IsValid() {
+		return e.Pos.String() + ": " + e.Msg
+	}
+
+	return e.Msg
+}
+
+// Errors is the list of the file errors the analysis continued past.
+type Errors []FileError
+
+func (e Errors) Error() string {
+	switch len(e) {
+	case 0:
+		return "no errors"
+	case 1:
+		return e[0].Error()
+	}
+
+	return fmt.Sprintf("%s (and %d more errors)", e[0], len(e)-1)
+}
+
+// Err returns nil when the list is empty, otherwise the list itself.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
@@ -0,0 +1,75 @@
+package gocognit
+
+import (
+	"go/ast"
+	"regexp"
+	"strings"
+)
+
+// FuncFilter selects the functions by their names, the zero value selects
+// all the functions.
+type FuncFilter struct {
+	Include *regexp.Regexp // the function name, as Stat.FuncName, must match it
+	Exclude *regexp.Regexp // the function name must not match it
+	Pkg     *regexp.Regexp // the package name must match it
+
+	// ExportedOnly selects the exported functions and the exported methods
+	// of the exported types only.
+	ExportedOnly bool
+}
+
+// Match reports whether the function is selected, the funcName is in the
+// form of Stat.FuncName.
+func (f FuncFilter) Match(pkgName, funcName string) bool {
+	if f.Pkg != nil && !f.Pkg.MatchString(pkgName) {
+		return false
+	}
+
+	if f.Include != nil && !f.Include.MatchString(funcName) {
+		return false
+	}
+
+	if f.Exclude != nil && f.Exclude.MatchString(funcName) {
+		return false
+	}
+
+	return !f.ExportedOnly || isExportedFunc(funcName)
+}
+
+func isExportedFunc(funcName string) bool {
+	recv, ok := receiverType(funcName)
+	if ok && !ast.IsExported(recv) {
+		return false
+	}
+
+	return ast.IsExported(funcName[strings.LastIndex(funcName, ".")+1:])
+}
+
+// regexpFlag is a flag.Value of an optional regexp, the empty string
+// unsets it.
+type regexpFlag struct {
+	re **regexp.Regexp
+}
+
+func (f regexpFlag) String() string {
+	if f.re == nil || *f.re == nil {
+		return ""
+	}
+
+	return (*f.re).String()
+}
+
+func (f regexpFlag) Set(s string) error {
+	if s == "" {
+		*f.re = nil
+		return nil
+	}
+
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return err
+	}
+
+	*f.re = re
+	return nil
+}
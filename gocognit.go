@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
 	"strconv"
 
 	"golang.org/x/tools/go/analysis"
@@ -16,8 +17,23 @@ type Stat struct {
 	PkgName     string
 	FuncName    string
 	Complexity  int
+	Cyclomatic  int
+	MaxNesting  int `json:",omitempty"`
+	Statements  int `json:",omitempty"`
+	Lines       int `json:",omitempty"`
+	Params      int `json:",omitempty"`
+	Results     int `json:",omitempty"`
 	Pos         token.Position
+	End         token.Position
 	Diagnostics []Diagnostic `json:",omitempty"`
+
+	// Halstead and MaintainabilityIndex are only calculated when the
+	// Halstead option is enabled.
+	Halstead             *Halstead `json:",omitempty"`
+	MaintainabilityIndex float64   `json:",omitempty"`
+
+	// Severity is only set when the Levels option is enabled.
+	Severity Severity `json:",omitempty"`
 }
 
 // Diagnostic contains information how the complexity increase.
@@ -78,6 +94,11 @@ func ComplexityStats(f *ast.File, fset *token.FileSet, stats []Stat) []Stat {
 
 // ComplexityStatsWithDiagnostic builds the complexity statistics with diagnostic.
 func ComplexityStatsWithDiagnostic(f *ast.File, fset *token.FileSet, stats []Stat, enableDiagnostics bool) []Stat {
+	return ComplexityStatsWithOptions(f, fset, stats, ScanOptions{Diagnostics: enableDiagnostics})
+}
+
+// ComplexityStatsWithOptions builds the complexity statistics using the options.
+func ComplexityStatsWithOptions(f *ast.File, fset *token.FileSet, stats []Stat, opts ScanOptions) []Stat {
 	for _, decl := range f.Decls {
 		if fn, ok := decl.(*ast.FuncDecl); ok {
 			d := parseDirective(fn.Doc)
@@ -85,15 +106,35 @@ func ComplexityStatsWithDiagnostic(f *ast.File, fset *token.FileSet, stats []Sta
 				continue
 			}
 
-			res := ScanComplexity(fn, enableDiagnostics)
+			if !opts.Filter.Match(f.Name.Name, funcName(fn)) {
+				continue
+			}
+
+			res := ScanComplexityWithOptions(fn, opts)
+			pos, end := fset.Position(fn.Pos()), fset.Position(fn.End())
 
-			stats = append(stats, Stat{
+			stat := Stat{
 				PkgName:     f.Name.Name,
 				FuncName:    funcName(fn),
 				Complexity:  res.Complexity,
+				Cyclomatic:  res.Cyclomatic,
+				MaxNesting:  res.MaxNesting,
+				Statements:  res.Statements,
+				Lines:       end.Line - pos.Line + 1,
+				Params:      res.Params,
+				Results:     res.Results,
 				Diagnostics: generateDiagnostics(fset, res.Diagnostics),
-				Pos:         fset.Position(fn.Pos()),
-			})
+				Pos:         pos,
+				End:         end,
+				Halstead:    res.Halstead,
+				Severity:    opts.Levels.Severity(res.Complexity),
+			}
+
+			if res.Halstead != nil {
+				stat.MaintainabilityIndex = MaintainabilityIndex(res.Halstead.Volume, stat.Cyclomatic, stat.Lines)
+			}
+
+			stats = append(stats, stat)
 		}
 	}
 
@@ -163,22 +204,125 @@ func Complexity(fn *ast.FuncDecl) int {
 
 // ScanComplexity scans the function declaration.
 func ScanComplexity(fn *ast.FuncDecl, includeDiagnostics bool) ScanResult {
+	return ScanComplexityWithOptions(fn, ScanOptions{Diagnostics: includeDiagnostics})
+}
+
+// ScanOptions is the options to scan the complexity.
+type ScanOptions struct {
+	Diagnostics bool   // include the diagnostics in the result
+	Rules       *Rules // the scoring rules, nil means DefaultRules
+
+	// DiscountErrorChecks enables the Go idiom mode, the trivial error
+	// propagation guards (if err != nil { return ... }) are not counted.
+	// The discounted guards are reported as diagnostics with zero increment.
+	DiscountErrorChecks bool
+
+	// TypesInfo is used to recognize the error values when available,
+	// otherwise they are recognized by their names.
+	TypesInfo *types.Info
+
+	// Halstead enables the Halstead metrics, the maintainability index of
+	// the statistics is calculated from them.
+	Halstead bool
+
+	// Filter selects the functions of the statistics.
+	Filter FuncFilter
+
+	// Levels sets the severity of the statistics.
+	Levels Levels
+}
+
+// ScanComplexityWithOptions scans the function declaration using the options.
+func ScanComplexityWithOptions(fn *ast.FuncDecl, opts ScanOptions) ScanResult {
+	rules := DefaultRules()
+	if opts.Rules != nil {
+		rules = *opts.Rules
+	}
+
 	v := complexityVisitor{
-		name:               fn.Name,
-		diagnosticsEnabled: includeDiagnostics,
+		name:                fn.Name,
+		cyclomatic:          1,
+		rules:               rules,
+		discountErrorChecks: opts.DiscountErrorChecks,
+		typesInfo:           opts.TypesInfo,
+		diagnosticsEnabled:  opts.Diagnostics,
 	}
 
 	ast.Walk(&v, fn)
 
+	var halstead *Halstead
+	if opts.Halstead {
+		h := countHalstead(fn)
+		halstead = &h
+	}
+
 	return ScanResult{
 		Diagnostics: v.diagnostics,
 		Complexity:  v.complexity,
+		Cyclomatic:  v.cyclomatic,
+		MaxNesting:  v.maxNesting,
+		Statements:  countStatements(fn.Body),
+		Params:      fn.Type.Params.NumFields(),
+		Results:     fn.Type.Results.NumFields(),
+		Halstead:    halstead,
 	}
 }
 
 type ScanResult struct {
 	Diagnostics []diagnostic
 	Complexity  int
+	Cyclomatic  int       // cyclomatic complexity, calculated by the same scan
+	MaxNesting  int       // deepest nesting level reached in the function
+	Statements  int       // number of statements, including the nested ones, see countStatements
+	Params      int       // number of parameters, the receiver is not counted
+	Results     int       // number of results
+	Halstead    *Halstead // nil unless the Halstead option is enabled
+}
+
+// countStatements counts the statements of the body. The blocks, the case
+// clauses and the labels are only containers so they are not counted, nor
+// are the init and post statements of if, for and switch, the assignment
+// of a type switch and the communication of a select case, they are a
+// part of the statement they belong to, e.g. for i := 0; i < n; i++ {} is
+// a single statement.
+func countStatements(body *ast.BlockStmt) int {
+	if body == nil {
+		return 0
+	}
+
+	var (
+		n      int
+		header = make(map[ast.Stmt]bool)
+	)
+	ast.Inspect(body, func(node ast.Node) bool {
+		stmt, ok := node.(ast.Stmt)
+		if !ok || header[stmt] {
+			return true
+		}
+
+		switch stmt := stmt.(type) {
+		case *ast.BlockStmt, *ast.CaseClause, *ast.EmptyStmt, *ast.LabeledStmt:
+			return true
+		case *ast.CommClause:
+			header[stmt.Comm] = true
+			return true
+		case *ast.IfStmt:
+			header[stmt.Init] = true
+		case *ast.ForStmt:
+			header[stmt.Init] = true
+			header[stmt.Post] = true
+		case *ast.SwitchStmt:
+			header[stmt.Init] = true
+		case *ast.TypeSwitchStmt:
+			header[stmt.Init] = true
+			header[stmt.Assign] = true
+		}
+
+		n++
+		return true
+	})
+
+	return n
 }
 
 type diagnostic struct {
@@ -190,46 +334,92 @@ type diagnostic struct {
 
 type complexityVisitor struct {
 	name            *ast.Ident
+	rules           Rules
 	complexity      int
-	nesting         int
+	cyclomatic      int
+	nesting         int // nesting level of the increments, weighted by the rules
+	depth           int // nesting depth of the structures
+	maxNesting      int
 	elseNodes       map[ast.Node]bool
 	calculatedExprs map[ast.Expr]bool
 
+	discountErrorChecks bool
+	typesInfo           *types.Info
+
 	diagnosticsEnabled bool
 	diagnostics        []diagnostic
 }
 
 func (v *complexityVisitor) incNesting() {
-	v.nesting++
+	v.enterNesting(1)
 }
 
 func (v *complexityVisitor) decNesting() {
-	v.nesting--
+	v.leaveNesting(1)
+}
+
+// enterNesting enters a structure adding inc to the nesting level of the
+// increments. The structure is a single level of the max nesting whatever
+// its increment is.
+func (v *complexityVisitor) enterNesting(inc int) {
+	v.nesting += inc
+	v.depth++
+
+	if v.depth > v.maxNesting {
+		v.maxNesting = v.depth
+	}
 }
 
-func (v *complexityVisitor) incComplexity(text string, pos token.Pos) {
-	v.complexity++
+func (v *complexityVisitor) leaveNesting(inc int) {
+	v.nesting -= inc
+	v.depth--
+}
+
+func (v *complexityVisitor) incComplexity(inc int, text string, pos token.Pos) {
+	if inc == 0 {
+		return
+	}
+
+	v.complexity += inc
 
 	if !v.diagnosticsEnabled {
 		return
 	}
 
 	v.diagnostics = append(v.diagnostics, diagnostic{
-		Inc:  1,
+		Inc:  inc,
 		Text: text,
 		Pos:  pos,
 	})
 }
 
-func (v *complexityVisitor) nestIncComplexity(text string, pos token.Pos) {
-	v.complexity += (v.nesting + 1)
+func (v *complexityVisitor) nestIncComplexity(inc int, text string, pos token.Pos) {
+	if inc == 0 {
+		return
+	}
+
+	inc += v.rules.Nesting * v.nesting
+	v.complexity += inc
 
 	if !v.diagnosticsEnabled {
 		return
 	}
 
 	v.diagnostics = append(v.diagnostics, diagnostic{
-		Inc:     v.nesting + 1,
+		Inc:     inc,
+		Nesting: v.nesting,
+		Text:    text,
+		Pos:     pos,
+	})
+}
+
+// discount records a construct which is not counted.
+func (v *complexityVisitor) discount(text string, pos token.Pos) {
+	if !v.diagnosticsEnabled {
+		return
+	}
+
+	v.diagnostics = append(v.diagnostics, diagnostic{
 		Nesting: v.nesting,
 		Text:    text,
 		Pos:     pos,
@@ -279,12 +469,20 @@ func (v *complexityVisitor) Visit(n ast.Node) ast.Visitor {
 		return v.visitTypeSwitchStmt(n)
 	case *ast.SelectStmt:
 		return v.visitSelectStmt(n)
+	case *ast.CaseClause:
+		return v.visitCaseClause(n)
+	case *ast.CommClause:
+		return v.visitCommClause(n)
 	case *ast.ForStmt:
 		return v.visitForStmt(n)
 	case *ast.RangeStmt:
 		return v.visitRangeStmt(n)
 	case *ast.FuncLit:
 		return v.visitFuncLit(n)
+	case *ast.GoStmt:
+		return v.visitGoStmt(n)
+	case *ast.DeferStmt:
+		return v.visitDeferStmt(n)
 	case *ast.BranchStmt:
 		return v.visitBranchStmt(n)
 	case *ast.BinaryExpr:
@@ -297,7 +495,13 @@ func (v *complexityVisitor) Visit(n ast.Node) ast.Visitor {
 }
 
 func (v *complexityVisitor) visitIfStmt(n *ast.IfStmt) ast.Visitor {
-	v.incIfComplexity(n, "if", n.Pos())
+	v.cyclomatic++
+
+	if x, ok := v.errorCheck(n); ok {
+		v.discount(fmt.Sprintf("if %s != nil", types.ExprString(x)), n.Pos())
+	} else {
+		v.incIfComplexity(n, "if", n.Pos())
+	}
 
 	if n := n.Init; n != nil {
 		ast.Walk(v, n)
@@ -310,7 +514,7 @@ func (v *complexityVisitor) visitIfStmt(n *ast.IfStmt) ast.Visitor {
 	v.decNesting()
 
 	if _, ok := n.Else.(*ast.BlockStmt); ok {
-		v.incComplexity("else", n.Else.Pos())
+		v.incComplexity(v.rules.Else, "else", n.Else.Pos())
 
 		ast.Walk(v, n.Else)
 	} else if _, ok := n.Else.(*ast.IfStmt); ok {
@@ -322,7 +526,7 @@ func (v *complexityVisitor) visitIfStmt(n *ast.IfStmt) ast.Visitor {
 }
 
 func (v *complexityVisitor) visitSwitchStmt(n *ast.SwitchStmt) ast.Visitor {
-	v.nestIncComplexity("switch", n.Pos())
+	v.nestIncComplexity(v.rules.Switch, "switch", n.Pos())
 
 	if n := n.Init; n != nil {
 		ast.Walk(v, n)
@@ -340,7 +544,7 @@ func (v *complexityVisitor) visitSwitchStmt(n *ast.SwitchStmt) ast.Visitor {
 }
 
 func (v *complexityVisitor) visitTypeSwitchStmt(n *ast.TypeSwitchStmt) ast.Visitor {
-	v.nestIncComplexity("switch", n.Pos())
+	v.nestIncComplexity(v.rules.Switch, "switch", n.Pos())
 
 	if n := n.Init; n != nil {
 		ast.Walk(v, n)
@@ -358,17 +562,41 @@ func (v *complexityVisitor) visitTypeSwitchStmt(n *ast.TypeSwitchStmt) ast.Visit
 }
 
 func (v *complexityVisitor) visitSelectStmt(n *ast.SelectStmt) ast.Visitor {
-	v.nestIncComplexity("select", n.Pos())
+	v.nestIncComplexity(v.rules.Select, "select", n.Pos())
 
 	v.incNesting()
-	ast.Walk(v, n.Body)
+	for _, stmt := range n.Body.List {
+		if c, ok := stmt.(*ast.CommClause); ok {
+			v.incCaseComplexity(c)
+		}
+
+		ast.Walk(v, stmt)
+	}
 	v.decNesting()
 
 	return nil
 }
 
+// incCaseComplexity increments the complexity of a select case. The case
+// is reported in the diagnostics even when it has no increment.
+func (v *complexityVisitor) incCaseComplexity(c *ast.CommClause) {
+	text := "case"
+	if c.Comm == nil {
+		text = "default"
+	}
+
+	if v.rules.SelectCase == 0 {
+		v.discount(text, c.Pos())
+		return
+	}
+
+	v.incComplexity(v.rules.SelectCase, text, c.Pos())
+}
+
 func (v *complexityVisitor) visitForStmt(n *ast.ForStmt) ast.Visitor {
-	v.nestIncComplexity("for", n.Pos())
+	v.cyclomatic++
+
+	v.nestIncComplexity(v.rules.For, "for", n.Pos())
 
 	if n := n.Init; n != nil {
 		ast.Walk(v, n)
@@ -390,7 +618,9 @@ func (v *complexityVisitor) visitForStmt(n *ast.ForStmt) ast.Visitor {
 }
 
 func (v *complexityVisitor) visitRangeStmt(n *ast.RangeStmt) ast.Visitor {
-	v.nestIncComplexity("for", n.Pos())
+	v.cyclomatic++
+
+	v.nestIncComplexity(v.rules.For, "for", n.Pos())
 
 	if n := n.Key; n != nil {
 		ast.Walk(v, n)
@@ -409,33 +639,91 @@ func (v *complexityVisitor) visitRangeStmt(n *ast.RangeStmt) ast.Visitor {
 	return nil
 }
 
+// visitCaseClause only counts the cyclomatic complexity, each case other
+// than default is a branch.
+func (v *complexityVisitor) visitCaseClause(n *ast.CaseClause) ast.Visitor {
+	if n.List != nil {
+		v.cyclomatic++
+	}
+
+	return v
+}
+
+// visitCommClause only counts the cyclomatic complexity, each case other
+// than default is a branch.
+func (v *complexityVisitor) visitCommClause(n *ast.CommClause) ast.Visitor {
+	if n.Comm != nil {
+		v.cyclomatic++
+	}
+
+	return v
+}
+
 func (v *complexityVisitor) visitFuncLit(n *ast.FuncLit) ast.Visitor {
 	ast.Walk(v, n.Type)
 
-	v.incNesting()
+	v.enterNesting(v.rules.Closure)
 	ast.Walk(v, n.Body)
-	v.decNesting()
+	v.leaveNesting(v.rules.Closure)
 
 	return nil
 }
 
+// visitGoStmt increments the complexity for spawning a goroutine. The body
+// of a function literal call, go func() { ... }(), is nested by the
+// function literal itself.
+func (v *complexityVisitor) visitGoStmt(n *ast.GoStmt) ast.Visitor {
+	v.incComplexity(v.rules.Go, "go", n.Pos())
+
+	return v
+}
+
+// visitDeferStmt increments the complexity for deferring a call, the same
+// way as visitGoStmt.
+func (v *complexityVisitor) visitDeferStmt(n *ast.DeferStmt) ast.Visitor {
+	v.incComplexity(v.rules.Defer, "defer", n.Pos())
+
+	return v
+}
+
 func (v *complexityVisitor) visitBranchStmt(n *ast.BranchStmt) ast.Visitor {
-	if n.Label != nil {
-		v.incComplexity(n.Tok.String(), n.Pos())
+	switch n.Tok {
+	case token.GOTO:
+		v.incComplexity(v.rules.Goto, branchText(n), n.Pos())
+	case token.FALLTHROUGH:
+		v.incComplexity(v.rules.Fallthrough, branchText(n), n.Pos())
+	default:
+		if n.Label != nil {
+			v.incComplexity(v.rules.Branch, branchText(n), n.Pos())
+		}
 	}
 
 	return v
 }
 
+// branchText returns the text of the branch statement including the
+// target label, e.g. "continue OUT".
+func branchText(n *ast.BranchStmt) string {
+	if n.Label == nil {
+		return n.Tok.String()
+	}
+
+	return n.Tok.String() + " " + n.Label.Name
+}
+
 func (v *complexityVisitor) visitBinaryExpr(n *ast.BinaryExpr) ast.Visitor {
+	if isBinaryLogicalOp(n.Op) {
+		v.cyclomatic++
+	}
+
 	if isBinaryLogicalOp(n.Op) && !v.isCalculated(n) {
-		ops := v.collectBinaryOps(n)
+		ops := v.collectLogicalOps(n)
 
 		var lastOp token.Token
 		for _, op := range ops {
-			if lastOp != op {
-				v.incComplexity(op.String(), n.OpPos)
-				lastOp = op
+			if lastOp != op.Tok {
+				v.incComplexity(v.rules.LogicalOp, op.Tok.String(), op.Pos)
+				lastOp = op.Tok
 			}
 		}
 	}
@@ -448,36 +736,54 @@ func (v *complexityVisitor) visitCallExpr(n *ast.CallExpr) ast.Visitor {
 		obj, name := callIdent.Obj, callIdent.Name
 		if obj == v.name.Obj && name == v.name.Name {
 			// called by same function directly (direct recursion)
-			v.incComplexity(name, n.Pos())
+			v.incComplexity(v.rules.Recursion, name, n.Pos())
 		}
 	}
 
 	return v
 }
 
-func (v *complexityVisitor) collectBinaryOps(exp ast.Expr) []token.Token {
+// logicalOp is a binary logical operator of a sequence.
+type logicalOp struct {
+	Tok token.Token
+	Pos token.Pos
+}
+
+// collectLogicalOps flattens the binary expression into the sequence of
+// its logical operators in the source order. Parentheses and negation end
+// the sequence, the expression inside them is a sequence of its own, e.g.
+// a && !(b && c) has two sequences of &&.
+func (v *complexityVisitor) collectLogicalOps(exp ast.Expr) []logicalOp {
 	v.markCalculated(exp)
 
 	if exp, ok := exp.(*ast.BinaryExpr); ok {
-		return mergeBinaryOps(v.collectBinaryOps(exp.X), exp.Op, v.collectBinaryOps(exp.Y))
+		return mergeLogicalOps(v.collectLogicalOps(exp.X), exp, v.collectLogicalOps(exp.Y))
 	}
 	return nil
 }
 
+func (v *complexityVisitor) errorCheck(n *ast.IfStmt) (ast.Expr, bool) {
+	if !v.discountErrorChecks {
+		return nil, false
+	}
+
+	return errorCheck(n, v.typesInfo)
+}
+
 func (v *complexityVisitor) incIfComplexity(n *ast.IfStmt, text string, pos token.Pos) {
 	if v.markedAsElseNode(n) {
-		v.incComplexity(text, pos)
+		v.incComplexity(v.rules.ElseIf, text, pos)
 	} else {
-		v.nestIncComplexity(text, pos)
+		v.nestIncComplexity(v.rules.If, text, pos)
 	}
 }
 
-func mergeBinaryOps(x []token.Token, op token.Token, y []token.Token) []token.Token {
-	var out []token.Token
+func mergeLogicalOps(x []logicalOp, exp *ast.BinaryExpr, y []logicalOp) []logicalOp {
+	var out []logicalOp
 	out = append(out, x...)
 
-	if isBinaryLogicalOp(op) {
-		out = append(out, op)
+	if isBinaryLogicalOp(exp.Op) {
+		out = append(out, logicalOp{Tok: exp.Op, Pos: exp.OpPos})
 	}
 
 	out = append(out, y...)
@@ -494,7 +800,9 @@ The gocognit analysis reports functions or methods which the complexity is over
 than the specified limit.`
 
 // Analyzer reports a diagnostic for every function or method which is
-// too complex specified by its -over flag.
+// too complex specified by its -over flag. When the -warn or -error flag is
+// set, they are used instead and the category of the diagnostic is the
+// severity, "warning" or "error".
 var Analyzer = &analysis.Analyzer{
 	Name:     "gocognit",
 	Doc:      Doc,
@@ -503,11 +811,27 @@ var Analyzer = &analysis.Analyzer{
 }
 
 var (
-	over int // -over flag
+	over      int              // -over flag
+	cycloOver int              // -cyclo-over flag
+	rules     = DefaultRules() // -rules flag
+	goIdiom   bool             // -go-idiom flag
+	levels    Levels           // -warn and -error flags
+
+	// -include-func, -exclude-func, -pkg and -exported-only flags
+	funcFilter FuncFilter
 )
 
 func init() {
 	Analyzer.Flags.IntVar(&over, "over", over, "show functions with complexity > N only")
+	Analyzer.Flags.IntVar(&cycloOver, "cyclo-over", cycloOver, "show functions with cyclomatic complexity > N, 0 disables it")
+	Analyzer.Flags.IntVar(&levels.Warn, "warn", levels.Warn, "report functions with complexity > N as warnings, 0 disables it")
+	Analyzer.Flags.IntVar(&levels.Error, "error", levels.Error, "report functions with complexity > N as errors, 0 disables it")
+	Analyzer.Flags.Var(&rules, "rules", "comma separated name=increment pairs overriding the scoring rules")
+	Analyzer.Flags.BoolVar(&goIdiom, "go-idiom", goIdiom, "do not count the trivial error checks (if err != nil { return ... })")
+	Analyzer.Flags.Var(regexpFlag{&funcFilter.Include}, "include-func", "check the functions which name matches the regexp only")
+	Analyzer.Flags.Var(regexpFlag{&funcFilter.Exclude}, "exclude-func", "do not check the functions which name matches the regexp")
+	Analyzer.Flags.Var(regexpFlag{&funcFilter.Pkg}, "pkg", "check the packages which name matches the regexp only")
+	Analyzer.Flags.BoolVar(&funcFilter.ExportedOnly, "exported-only", funcFilter.ExportedOnly, "check the exported functions and methods only")
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
@@ -525,12 +849,34 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		}
 
 		fnName := funcName(funcDecl)
+		if !funcFilter.Match(pass.Pkg.Name(), fnName) {
+			return
+		}
 
-		fnComplexity := Complexity(funcDecl)
-
-		if fnComplexity > over {
+		res := ScanComplexityWithOptions(funcDecl, ScanOptions{
+			Rules:               &rules,
+			DiscountErrorChecks: goIdiom,
+			TypesInfo:           pass.TypesInfo,
+		})
+		fnComplexity := res.Complexity
+
+		switch {
+		case levels.Enabled():
+			if severity := levels.Severity(fnComplexity); severity != "" {
+				pass.Report(analysis.Diagnostic{
+					Pos:      funcDecl.Pos(),
+					Category: string(severity),
+					Message: fmt.Sprintf("cognitive complexity %d of func %s is high (> %d)",
+						fnComplexity, fnName, levels.Limit(severity)),
+				})
+			}
+		case fnComplexity > over:
 			pass.Reportf(funcDecl.Pos(), "cognitive complexity %d of func %s is high (> %d)", fnComplexity, fnName, over)
 		}
+
+		if cycloOver > 0 && res.Cyclomatic > cycloOver {
+			pass.Reportf(funcDecl.Pos(), "cyclomatic complexity %d of func %s is high (> %d)", res.Cyclomatic, fnName, cycloOver)
+		}
 	})
 
 	return nil, nil
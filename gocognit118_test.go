@@ -4,6 +4,9 @@
 package gocognit_test
 
 import (
+	"go/parser"
+	"go/token"
+	"reflect"
 	"testing"
 
 	"github.com/uudashr/gocognit"
@@ -15,3 +18,35 @@ func TestAnalyzer_Generics(t *testing.T) {
 	gocognit.Analyzer.Flags.Set("over", "0")
 	analysistest.Run(t, testdata, gocognit.Analyzer, "c")
 }
+
+func TestTypeStats_Generics(t *testing.T) {
+	src := `package p
+
+type Pair[K comparable, V any] struct{}
+
+func (p Pair[K, V]) Key(a bool) int {
+	if a {
+		return 1
+	}
+	return 0
+}
+
+func (p *Pair[_, V]) Value() int {
+	return 0
+}
+`
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := gocognit.TypeStats(gocognit.ComplexityStats(f, fset, nil))
+	want := []gocognit.GroupStat{
+		{PkgName: "p", Dir: ".", TypeName: "Pair", Funcs: 2, Complexity: 1, MaxComplexity: 1, Average: 0.5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got type stats %+v, want %+v", got, want)
+	}
+}
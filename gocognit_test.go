@@ -1,6 +1,15 @@
 package gocognit_test
 
 import (
+	"context"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/uudashr/gocognit"
@@ -24,3 +33,712 @@ func TestAnalyzerComplex(t *testing.T) {
 	gocognit.Analyzer.Flags.Set("over", "0")
 	analysistest.Run(t, testdata, gocognit.Analyzer, "d")
 }
+
+func TestAnalyzerRules(t *testing.T) {
+	testdata := analysistest.TestData()
+	gocognit.Analyzer.Flags.Set("over", "0")
+	defer gocognit.Analyzer.Flags.Set("rules", gocognit.Analyzer.Flags.Lookup("rules").Value.String())
+	gocognit.Analyzer.Flags.Set("rules", "else=0,closure=0,nesting=0,recursion=2")
+	analysistest.Run(t, testdata, gocognit.Analyzer, "e")
+}
+
+func TestRulesSet_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"else=2,bogus=1",
+		"else=2,if",
+		"else=2,if=x",
+		"else=2,if=-1",
+	} {
+		rules := gocognit.DefaultRules()
+		if err := rules.Set(s); err == nil {
+			t.Errorf("Set(%q) got no error, want error", s)
+		}
+
+		if rules != gocognit.DefaultRules() {
+			t.Errorf("Set(%q) changed the rules to %s, want unchanged", s, rules.String())
+		}
+	}
+}
+
+func TestAnalyzerFallthrough(t *testing.T) {
+	testdata := analysistest.TestData()
+	gocognit.Analyzer.Flags.Set("over", "0")
+	defer gocognit.Analyzer.Flags.Set("rules", gocognit.Analyzer.Flags.Lookup("rules").Value.String())
+	gocognit.Analyzer.Flags.Set("rules", "fallthrough=1")
+	analysistest.Run(t, testdata, gocognit.Analyzer, "f")
+}
+
+func TestScanComplexity_Jumps(t *testing.T) {
+	want := map[string][]string{
+		"Goto":           {"+1 if", "+1 goto LOOP"},
+		"LabeledBreak":   {"+1 for", "+2 (nesting=1) for", "+3 (nesting=2) if", "+1 break OUTER"},
+		"UnlabeledBreak": {"+1 for", "+2 (nesting=1) if"},
+		"Fallthrough":    {"+1 switch"},
+	}
+
+	testDiagnostics(t, "f", gocognit.ScanOptions{Diagnostics: true}, want)
+}
+
+func TestAnalyzerGoIdiom(t *testing.T) {
+	testdata := analysistest.TestData()
+	gocognit.Analyzer.Flags.Set("over", "0")
+	gocognit.Analyzer.Flags.Set("go-idiom", "true")
+	defer gocognit.Analyzer.Flags.Set("go-idiom", "false")
+	analysistest.Run(t, testdata, gocognit.Analyzer, "g")
+}
+
+func TestScanComplexity_GoIdiomWithoutTypes(t *testing.T) {
+	want := map[string][]string{
+		"Parse":      {"+0 if err != nil", "+1 if"},
+		"Wrap":       {"+0 if err != nil"},
+		"Typed":      {"+1 if"}, // not recognized without the type information
+		"NotTrivial": {"+1 if"},
+		"NotError":   {"+1 if"},
+		"Nested":     {"+1 for", "+0 (nesting=1) if err != nil"},
+	}
+
+	opts := gocognit.ScanOptions{
+		Diagnostics:         true,
+		DiscountErrorChecks: true,
+	}
+
+	testDiagnostics(t, "g", opts, want)
+}
+
+func TestAnalyzerConcurrency(t *testing.T) {
+	testdata := analysistest.TestData()
+	gocognit.Analyzer.Flags.Set("over", "0")
+	defer gocognit.Analyzer.Flags.Set("rules", gocognit.Analyzer.Flags.Lookup("rules").Value.String())
+	gocognit.Analyzer.Flags.Set("rules", "go=1,defer=1,selectcase=1")
+	analysistest.Run(t, testdata, gocognit.Analyzer, "h")
+}
+
+func TestScanComplexity_SelectCases(t *testing.T) {
+	want := map[string][]string{
+		"Spawn":    {"+1 for", "+3 (nesting=2) if"},
+		"Deferred": nil,
+		"Receive": {
+			"+1 select",
+			"+0 (nesting=1) case",
+			"+2 (nesting=1) if",
+			"+0 (nesting=1) case",
+			"+0 (nesting=1) case",
+			"+0 (nesting=1) default",
+		},
+	}
+
+	testDiagnostics(t, "h", gocognit.ScanOptions{Diagnostics: true}, want)
+}
+
+// testDiagnostics checks the diagnostics of each function of the testdata
+// package, in the form of "<increment> <text>".
+func testDiagnostics(t *testing.T, pkg string, opts gocognit.ScanOptions, want map[string][]string) {
+	t.Helper()
+
+	testDiagnosticsFormat(t, pkg, opts, want, func(d gocognit.Diagnostic) string {
+		return d.String() + " " + d.Text
+	})
+}
+
+// testDiagnosticsFormat checks the diagnostics of each function of the
+// testdata package, in the form given by format.
+func testDiagnosticsFormat(t *testing.T, pkg string, opts gocognit.ScanOptions, want map[string][]string, format func(gocognit.Diagnostic) string) {
+	t.Helper()
+
+	dir := filepath.Join(analysistest.TestData(), "src", pkg)
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stats []gocognit.Stat
+	for _, p := range pkgs {
+		for _, f := range p.Files {
+			stats = gocognit.ComplexityStatsWithOptions(f, fset, stats, opts)
+		}
+	}
+
+	for _, stat := range stats {
+		var got []string
+		for _, d := range stat.Diagnostics {
+			got = append(got, format(d))
+		}
+
+		if !reflect.DeepEqual(got, want[stat.FuncName]) {
+			t.Errorf("%s: got diagnostics %q, want %q", stat.FuncName, got, want[stat.FuncName])
+		}
+	}
+}
+
+func TestScanComplexity_LogicalOps(t *testing.T) {
+	want := map[string][]string{
+		"Negation":   {"+1 || 4:19", "+1 && 4:13"},
+		"Nested":     {"+1 && 8:11", "+1 || 8:17"},
+		"Mixed":      {"+1 && 12:11", "+1 || 12:16", "+1 && 12:21"},
+		"Comparison": {"+1 && 16:11", "+1 && 16:17"},
+	}
+
+	testDiagnosticsFormat(t, "l", gocognit.ScanOptions{Diagnostics: true}, want, func(d gocognit.Diagnostic) string {
+		return d.String() + " " + d.Text + " " + d.Pos.String()
+	})
+}
+
+func TestAnalyzerCyclomatic(t *testing.T) {
+	testdata := analysistest.TestData()
+	gocognit.Analyzer.Flags.Set("over", "100")
+	gocognit.Analyzer.Flags.Set("cyclo-over", "2")
+	defer gocognit.Analyzer.Flags.Set("cyclo-over", "0")
+	analysistest.Run(t, testdata, gocognit.Analyzer, "i")
+}
+
+func TestComplexityStats_Metrics(t *testing.T) {
+	src := `package p
+
+func (t *T) Sum(ints []int, skip func(int) bool) (total int, err error) {
+	for _, n := range ints {
+		if skip(n) {
+			continue
+		}
+
+		total += n
+	}
+
+	return total, nil
+}
+
+func Run(f func() error, args ...string) error {
+	go func() {
+		_ = f()
+	}()
+
+	switch len(args) {
+	case 0:
+		return nil
+	}
+
+	return f()
+}
+`
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type metrics struct {
+		MaxNesting, Statements, Lines, Params, Results int
+	}
+
+	want := map[string]metrics{
+		"(*T).Sum": {MaxNesting: 2, Statements: 5, Lines: 11, Params: 2, Results: 2},
+		"Run":      {MaxNesting: 1, Statements: 5, Lines: 12, Params: 2, Results: 1},
+	}
+
+	stats := gocognit.ComplexityStats(f, fset, nil)
+	for _, stat := range stats {
+		got := metrics{stat.MaxNesting, stat.Statements, stat.Lines, stat.Params, stat.Results}
+		if got != want[stat.FuncName] {
+			t.Errorf("%s: got metrics %+v, want %+v", stat.FuncName, got, want[stat.FuncName])
+		}
+	}
+}
+
+func TestComplexityStats_Statements(t *testing.T) {
+	src := `package p
+
+func Loop(n int) (total int) {
+	for i := 0; i < n; i++ {
+		total += i
+	}
+	return total
+}
+
+func Labeled(rows [][]int) {
+OUTER:
+	for _, row := range rows {
+		for i := 0; i < len(row); i++ {
+			if v := row[i]; v < 0 {
+				continue OUTER
+			}
+		}
+	}
+}
+
+func Headers(v interface{}, ch chan int) {
+	switch x := v.(type) {
+	case int:
+		_ = x
+	}
+
+	select {
+	case n := <-ch:
+		_ = n
+	default:
+	}
+}
+`
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{
+		"Loop":    3, // for, total += i, return
+		"Labeled": 4, // range, for, if, continue
+		"Headers": 4, // switch, _ = x, select, _ = n
+	}
+
+	for _, stat := range gocognit.ComplexityStats(f, fset, nil) {
+		if stat.Statements != want[stat.FuncName] {
+			t.Errorf("%s: got %d statements, want %d", stat.FuncName, stat.Statements, want[stat.FuncName])
+		}
+	}
+}
+
+func TestComplexityStats_MaxNestingRules(t *testing.T) {
+	src := `package p
+
+func Run(items []int) {
+	for range items {
+		go func() {
+			if len(items) > 0 {
+				return
+			}
+		}()
+	}
+}
+`
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"closure=1", "closure=3", "closure=0", "nesting=0"} {
+		rules := gocognit.DefaultRules()
+		if err := rules.Set(s); err != nil {
+			t.Fatal(err)
+		}
+
+		stats := gocognit.ComplexityStatsWithOptions(f, fset, nil, gocognit.ScanOptions{Rules: &rules})
+		if got := stats[0].MaxNesting; got != 3 {
+			t.Errorf("rules %s: got max nesting %d, want 3", s, got)
+		}
+	}
+}
+
+func TestComplexityStats_Halstead(t *testing.T) {
+	src := `package p
+
+func Add(a, b int) int {
+	return a + b
+}
+`
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := gocognit.ComplexityStatsWithOptions(f, fset, nil, gocognit.ScanOptions{Halstead: true})
+	if len(stats) != 1 {
+		t.Fatalf("got %d stats, want 1", len(stats))
+	}
+
+	// operators: func, return, +
+	// operands: Add, a, b, int, int, a, b
+	h := stats[0].Halstead
+	if h == nil {
+		t.Fatal("got nil Halstead metrics")
+	}
+
+	if got, want := [4]int{h.DistinctOperators, h.DistinctOperands, h.Operators, h.Operands}, [4]int{3, 4, 3, 7}; got != want {
+		t.Errorf("got counts %v, want %v", got, want)
+	}
+
+	floats := []struct {
+		name      string
+		got, want float64
+	}{
+		{"Volume", h.Volume, 28.07},
+		{"Difficulty", h.Difficulty, 2.625},
+		{"Effort", h.Effort, 73.69},
+		{"MaintainabilityIndex", stats[0].MaintainabilityIndex, 79.32},
+	}
+	for _, f := range floats {
+		if math.Abs(f.got-f.want) > 0.01 {
+			t.Errorf("got %s %.4f, want %.2f", f.name, f.got, f.want)
+		}
+	}
+}
+
+func TestTypeStats(t *testing.T) {
+	src := `package p
+
+type T struct{}
+
+func (t T) Value(a bool) int {
+	if a {
+		return 1
+	}
+	return 0
+}
+
+func (t *T) Pointer(a, b bool) int {
+	if a && b {
+		return 1
+	}
+	return 0
+}
+
+func Func(a bool) int {
+	if a {
+		return 1
+	}
+	return 0
+}
+`
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p/p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := gocognit.ComplexityStats(f, fset, nil)
+
+	got := gocognit.TypeStats(stats)
+	want := []gocognit.GroupStat{
+		{PkgName: "p", Dir: "p", TypeName: "T", Funcs: 2, Complexity: 3, MaxComplexity: 2, Average: 1.5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got type stats %+v, want %+v", got, want)
+	}
+
+	got = gocognit.PackageStats(stats)
+	want = []gocognit.GroupStat{
+		{PkgName: "p", Dir: "p", Funcs: 3, Complexity: 4, MaxComplexity: 2, Average: 4.0 / 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got package stats %+v, want %+v", got, want)
+	}
+}
+
+func TestAnalyzeDir(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filepath.Join("testdata", "src", "a", "a.go"), nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := gocognit.ComplexityStats(f, fset, nil)
+
+	for _, concurrency := range []int{1, 4} {
+		got, err := gocognit.AnalyzeDir(context.Background(), filepath.Join("testdata", "src"), gocognit.Options{
+			Ignore:      regexp.MustCompile(`src[/\\][b-z]`),
+			Concurrency: concurrency,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("concurrency %d: got %d stats, want %d stats of a.go", concurrency, len(got), len(want))
+		}
+	}
+}
+
+func TestAnalyzeDir_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := gocognit.AnalyzeDir(ctx, filepath.Join("testdata", "src"), gocognit.Options{})
+	if err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestAnalyzePackages(t *testing.T) {
+	stats, err := gocognit.AnalyzePackages(context.Background(), []string{"./testdata/src/g"}, gocognit.Options{
+		ScanOptions: gocognit.ScanOptions{DiscountErrorChecks: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := gocognit.AnalyzeDir(context.Background(), filepath.Join("testdata", "src", "g"), gocognit.Options{
+		ScanOptions: gocognit.ScanOptions{DiscountErrorChecks: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) == 0 || len(stats) != len(files) {
+		t.Fatalf("got %d stats, want %d", len(stats), len(files))
+	}
+
+	// the error values are recognized by their types, not by their names
+	var differ bool
+	for i := range stats {
+		if stats[i].Complexity != files[i].Complexity {
+			differ = true
+		}
+	}
+
+	if !differ {
+		t.Error("got the same complexities with and without the type information")
+	}
+}
+
+func TestAnalyzePackages_Exclude(t *testing.T) {
+	for _, tt := range []struct {
+		pattern string
+		want    int
+	}{
+		{"other.go", 6},
+		{"g.go", 0},
+		{"testdata/src/g", 0},
+		{"src", 0},
+	} {
+		stats, err := gocognit.AnalyzePackages(context.Background(), []string{"./testdata/src/g"}, gocognit.Options{
+			Exclude: []string{tt.pattern},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(stats) != tt.want {
+			t.Errorf("exclude %q: got %d stats, want %d", tt.pattern, len(stats), tt.want)
+		}
+	}
+}
+
+func TestAnalyzeSource(t *testing.T) {
+	src := `package p
+
+func Unsaved(a bool) int {
+	if a {
+		return 1
+	}
+	return 0
+}
+`
+
+	for name, analyze := range map[string]func() ([]gocognit.Stat, error){
+		"bytes": func() ([]gocognit.Stat, error) {
+			return gocognit.AnalyzeSource("unsaved.go", []byte(src), gocognit.ScanOptions{})
+		},
+		"reader": func() ([]gocognit.Stat, error) {
+			return gocognit.AnalyzeReader("unsaved.go", strings.NewReader(src), gocognit.ScanOptions{})
+		},
+	} {
+		stats, err := analyze()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if len(stats) != 1 {
+			t.Fatalf("%s: got %d stats, want 1", name, len(stats))
+		}
+
+		if got, want := stats[0].String(), "1 p Unsaved unsaved.go:3:1"; got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+
+	if _, err := gocognit.AnalyzeSource("broken.go", []byte("package p\nfunc {"), gocognit.ScanOptions{}); err == nil {
+		t.Error("got no error for invalid source")
+	}
+}
+
+func TestAnalyzeDir_ParseErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"good.go":   "package p\n\nfunc Good(a bool) int {\n\tif a {\n\t\treturn 1\n\t}\n\treturn 0\n}\n",
+		"broken.go": "package p\n\nfunc Before() {}\n\nfunc Broken() {\n\tif {\n}\n\nfunc After() {}\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := gocognit.AnalyzeDir(context.Background(), dir, gocognit.Options{})
+
+	errs, ok := err.(gocognit.Errors)
+	if !ok || len(errs) == 0 {
+		t.Fatalf("got error %v, want Errors", err)
+	}
+
+	for _, e := range errs {
+		if e.Pos.Filename != filepath.Join(dir, "broken.go") {
+			t.Errorf("got error %v, want the errors of broken.go only", e)
+		}
+	}
+
+	var got []string
+	for _, stat := range stats {
+		got = append(got, stat.FuncName)
+	}
+
+	if want := []string{"Before", "Good"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got functions %q, want %q", got, want)
+	}
+
+	stats, err = gocognit.AnalyzeDir(context.Background(), dir, gocognit.Options{Strict: true})
+	if err == nil || stats != nil {
+		t.Errorf("got %d stats and error %v, want the error only with strict option", len(stats), err)
+	}
+}
+
+func TestAnalyzeDir_Exclude(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{
+		"p.go",
+		"p_gen.go",
+		"vendor/v/v.go",
+		"testdata/t.go",
+		"node_modules/n.go",
+		".git/g.go",
+		"_old/o.go",
+		"sub/s.go",
+		"sub/legacy/l.go",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte("package p\n\nfunc F() {}\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := gocognit.AnalyzeDir(context.Background(), dir, gocognit.Options{
+		Exclude: []string{"*_gen.go", "sub/legacy"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, stat := range stats {
+		rel, err := filepath.Rel(dir, stat.Pos.Filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got = append(got, filepath.ToSlash(rel))
+	}
+
+	if want := []string{"p.go", "sub/s.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got files %q, want %q", got, want)
+	}
+
+	// the skipped directories can be given explicitly
+	stats, err = gocognit.AnalyzeDir(context.Background(), filepath.Join(dir, "vendor"), gocognit.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 1 {
+		t.Errorf("got %d stats of the vendor directory, want 1", len(stats))
+	}
+
+	if _, err := gocognit.AnalyzeDir(context.Background(), dir, gocognit.Options{Exclude: []string{"["}}); err == nil {
+		t.Error("got no error for malformed pattern")
+	}
+}
+
+func TestAnalyzerFuncFilter(t *testing.T) {
+	testdata := analysistest.TestData()
+	gocognit.Analyzer.Flags.Set("over", "0")
+	gocognit.Analyzer.Flags.Set("exported-only", "true")
+	gocognit.Analyzer.Flags.Set("exclude-func", `\.String$`)
+	defer gocognit.Analyzer.Flags.Set("exported-only", "false")
+	defer gocognit.Analyzer.Flags.Set("exclude-func", "")
+	analysistest.Run(t, testdata, gocognit.Analyzer, "j")
+}
+
+func TestAnalyzerLevels(t *testing.T) {
+	testdata := analysistest.TestData()
+	gocognit.Analyzer.Flags.Set("warn", "2")
+	gocognit.Analyzer.Flags.Set("error", "5")
+	defer gocognit.Analyzer.Flags.Set("warn", "0")
+	defer gocognit.Analyzer.Flags.Set("error", "0")
+	results := analysistest.Run(t, testdata, gocognit.Analyzer, "k")
+
+	var categories []string
+	for _, res := range results {
+		for _, diag := range res.Diagnostics {
+			categories = append(categories, diag.Category)
+		}
+	}
+
+	if want := []string{"warning", "error"}; !reflect.DeepEqual(categories, want) {
+		t.Errorf("got categories %q, want %q", categories, want)
+	}
+}
+
+func TestLevels_Severity(t *testing.T) {
+	tests := []struct {
+		name       string
+		levels     gocognit.Levels
+		complexity int
+		want       gocognit.Severity
+	}{
+		{"disabled", gocognit.Levels{}, 100, ""},
+		{"below warn", gocognit.Levels{Warn: 10, Error: 20}, 10, ""},
+		{"warn", gocognit.Levels{Warn: 10, Error: 20}, 11, gocognit.SeverityWarning},
+		{"error", gocognit.Levels{Warn: 10, Error: 20}, 21, gocognit.SeverityError},
+		{"error only", gocognit.Levels{Error: 20}, 15, ""},
+		{"warn only", gocognit.Levels{Warn: 10}, 50, gocognit.SeverityWarning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.levels.Severity(tt.complexity); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuncFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   gocognit.FuncFilter
+		pkgName  string
+		funcName string
+		want     bool
+	}{
+		{"zero", gocognit.FuncFilter{}, "p", "f", true},
+		{"include", gocognit.FuncFilter{Include: regexp.MustCompile(`^Parse`)}, "p", "ParseInt", true},
+		{"not included", gocognit.FuncFilter{Include: regexp.MustCompile(`^Parse`)}, "p", "Format", false},
+		{"exclude method", gocognit.FuncFilter{Exclude: regexp.MustCompile(`\.MarshalJSON$`)}, "p", "(*T).MarshalJSON", false},
+		{"pkg", gocognit.FuncFilter{Pkg: regexp.MustCompile(`^p$`)}, "p", "F", true},
+		{"other pkg", gocognit.FuncFilter{Pkg: regexp.MustCompile(`^p$`)}, "q", "F", false},
+		{"exported", gocognit.FuncFilter{ExportedOnly: true}, "p", "F", true},
+		{"unexported", gocognit.FuncFilter{ExportedOnly: true}, "p", "f", false},
+		{"exported method", gocognit.FuncFilter{ExportedOnly: true}, "p", "(*T).F", true},
+		{"unexported type", gocognit.FuncFilter{ExportedOnly: true}, "p", "(t).F", false},
+		{"unexported method", gocognit.FuncFilter{ExportedOnly: true}, "p", "(T).f", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(tt.pkgName, tt.funcName); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
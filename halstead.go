@@ -0,0 +1,176 @@
+package gocognit
+
+import (
+	"go/ast"
+	"math"
+)
+
+// Halstead is the Halstead metrics of a function.
+//
+// The operators are the keywords, the operators and the punctuation of the
+// calls, indexes, selectors and literals. The operands are the identifiers
+// and the basic literals.
+type Halstead struct {
+	DistinctOperators int // n1
+	DistinctOperands  int // n2
+	Operators         int // N1
+	Operands          int // N2
+
+	Volume     float64 // (N1 + N2) * log2(n1 + n2)
+	Difficulty float64 // n1 / 2 * N2 / n2
+	Effort     float64 // Difficulty * Volume
+}
+
+func newHalstead(operators, operands map[string]int) Halstead {
+	h := Halstead{
+		DistinctOperators: len(operators),
+		DistinctOperands:  len(operands),
+	}
+
+	for _, n := range operators {
+		h.Operators += n
+	}
+
+	for _, n := range operands {
+		h.Operands += n
+	}
+
+	vocabulary := h.DistinctOperators + h.DistinctOperands
+	if vocabulary > 0 {
+		h.Volume = float64(h.Operators+h.Operands) * math.Log2(float64(vocabulary))
+	}
+
+	if h.DistinctOperands > 0 {
+		h.Difficulty = float64(h.DistinctOperators) / 2 * float64(h.Operands) / float64(h.DistinctOperands)
+	}
+
+	h.Effort = h.Difficulty * h.Volume
+
+	return h
+}
+
+// MaintainabilityIndex calculates the maintainability index from the
+// Halstead volume, the cyclomatic complexity and the lines of code.
+//
+// It uses the normalized variant, ranging from 0 (hard to maintain) to 100:
+//
+//	max(0, (171 - 5.2*ln(volume) - 0.23*cyclomatic - 16.2*ln(lines)) * 100 / 171)
+func MaintainabilityIndex(volume float64, cyclomatic, lines int) float64 {
+	mi := 171.0 - 0.23*float64(cyclomatic)
+	if volume > 0 {
+		mi -= 5.2 * math.Log(volume)
+	}
+
+	if lines > 0 {
+		mi -= 16.2 * math.Log(float64(lines))
+	}
+
+	return math.Max(0, math.Min(100, mi*100/171))
+}
+
+// countHalstead counts the operators and the operands of the function,
+// including its signature.
+func countHalstead(fn *ast.FuncDecl) Halstead {
+	operators := make(map[string]int)
+	operands := make(map[string]int)
+
+	ast.Inspect(fn, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CommentGroup:
+			return false
+		case *ast.Ident:
+			operands[n.Name]++
+		case *ast.BasicLit:
+			operands[n.Value]++
+		default:
+			if op := halsteadOperator(n); op != "" {
+				operators[op]++
+			}
+
+			if n, ok := n.(*ast.IfStmt); ok && n.Else != nil {
+				operators["else"]++
+			}
+		}
+
+		return true
+	})
+
+	return newHalstead(operators, operands)
+}
+
+func halsteadOperator(n ast.Node) string {
+	switch n := n.(type) {
+	case *ast.BinaryExpr:
+		return n.Op.String()
+	case *ast.UnaryExpr:
+		return n.Op.String()
+	case *ast.StarExpr:
+		return "*"
+	case *ast.AssignStmt:
+		return n.Tok.String()
+	case *ast.IncDecStmt:
+		return n.Tok.String()
+	case *ast.SendStmt:
+		return "<-"
+	case *ast.CallExpr:
+		return "()"
+	case *ast.IndexExpr:
+		return "[]"
+	case *ast.SliceExpr:
+		return "[:]"
+	case *ast.SelectorExpr:
+		return "."
+	case *ast.TypeAssertExpr:
+		return ".()"
+	case *ast.CompositeLit:
+		return "{}"
+	case *ast.KeyValueExpr:
+		return ":"
+	case *ast.Ellipsis:
+		return "..."
+	case *ast.FuncType:
+		return "func"
+	case *ast.ArrayType:
+		return "[]"
+	case *ast.MapType:
+		return "map"
+	case *ast.ChanType:
+		return "chan"
+	case *ast.StructType:
+		return "struct"
+	case *ast.InterfaceType:
+		return "interface"
+	case *ast.GenDecl:
+		return n.Tok.String()
+	case *ast.IfStmt:
+		return "if"
+	case *ast.ForStmt:
+		return "for"
+	case *ast.RangeStmt:
+		return "range"
+	case *ast.SwitchStmt, *ast.TypeSwitchStmt:
+		return "switch"
+	case *ast.SelectStmt:
+		return "select"
+	case *ast.CaseClause:
+		if n.List == nil {
+			return "default"
+		}
+		return "case"
+	case *ast.CommClause:
+		if n.Comm == nil {
+			return "default"
+		}
+		return "case"
+	case *ast.ReturnStmt:
+		return "return"
+	case *ast.GoStmt:
+		return "go"
+	case *ast.DeferStmt:
+		return "defer"
+	case *ast.BranchStmt:
+		return n.Tok.String()
+	}
+
+	return typeParamsOperator(n)
+}
@@ -0,0 +1,14 @@
+//go:build !go1.18
+// +build !go1.18
+
+package gocognit
+
+import (
+	"go/ast"
+)
+
+// typeParamsOperator returns the operator of the type parameters
+// expressions, or empty string.
+func typeParamsOperator(n ast.Node) string {
+	return ""
+}
@@ -0,0 +1,93 @@
+package gocognit
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+)
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// errorCheck returns the error value checked by a trivial error propagation
+// guard, the if statement in the form:
+//
+//	if err != nil {
+//		return ...
+//	}
+//
+// The error value is recognized by its type when the type information is
+// available, otherwise by its name.
+func errorCheck(n *ast.IfStmt, info *types.Info) (ast.Expr, bool) {
+	if n.Else != nil || len(n.Body.List) != 1 {
+		return nil, false
+	}
+
+	if _, ok := n.Body.List[0].(*ast.ReturnStmt); !ok {
+		return nil, false
+	}
+
+	cond, ok := unparen(n.Cond).(*ast.BinaryExpr)
+	if !ok || cond.Op != token.NEQ {
+		return nil, false
+	}
+
+	x, y := unparen(cond.X), unparen(cond.Y)
+	if isNil(x, info) {
+		x, y = y, x
+	}
+
+	if !isNil(y, info) {
+		return nil, false
+	}
+
+	return x, isErrorValue(x, info)
+}
+
+func isNil(e ast.Expr, info *types.Info) bool {
+	if info != nil {
+		if tv, ok := info.Types[e]; ok {
+			return tv.IsNil()
+		}
+	}
+
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == "nil"
+}
+
+func isErrorValue(e ast.Expr, info *types.Info) bool {
+	if info != nil {
+		if tv, ok := info.Types[e]; ok {
+			return types.Implements(tv.Type, errorType)
+		}
+	}
+
+	var name string
+	switch e := e.(type) {
+	case *ast.Ident:
+		name = e.Name
+	case *ast.SelectorExpr:
+		name = e.Sel.Name
+	default:
+		return false
+	}
+
+	if name == "err" || strings.HasSuffix(name, "Err") {
+		return true
+	}
+
+	// errFoo
+	return len(name) > 3 && strings.HasPrefix(name, "err") && unicode.IsUpper(rune(name[3]))
+}
+
+func unparen(e ast.Expr) ast.Expr {
+	for {
+		p, ok := e.(*ast.ParenExpr)
+		if !ok {
+			return e
+		}
+
+		e = p.X
+	}
+}
@@ -0,0 +1,133 @@
+package gocognit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rules defines the increment of each construct. An increment of zero
+// disables the construct. The zero value disables every construct, use
+// DefaultRules as the starting point.
+//
+// The constructs marked as nesting receive an additional increment of
+// Nesting for each nesting level they are in.
+type Rules struct {
+	If          int // if, nesting
+	ElseIf      int // else if
+	Else        int // else
+	Switch      int // switch and type switch, nesting
+	Select      int // select, nesting
+	SelectCase  int // each case of select, including default
+	For         int // for and for range, nesting
+	Goto        int // goto LABEL
+	Branch      int // break LABEL, continue LABEL
+	Fallthrough int // fallthrough
+	LogicalOp   int // each sequence of like binary logical operators
+	Recursion   int // each direct recursive call
+	Closure     int // nesting level added by a function literal
+	Go          int // go statement
+	Defer       int // defer statement
+	Nesting     int // increment for each nesting level
+}
+
+// DefaultRules returns the rules as described in the README.
+func DefaultRules() Rules {
+	return Rules{
+		If:        1,
+		ElseIf:    1,
+		Else:      1,
+		Switch:    1,
+		Select:    1,
+		For:       1,
+		Goto:      1,
+		Branch:    1,
+		LogicalOp: 1,
+		Recursion: 1,
+		Closure:   1,
+		Nesting:   1,
+	}
+}
+
+func (r *Rules) fields() []ruleField {
+	return []ruleField{
+		{"if", &r.If},
+		{"elseif", &r.ElseIf},
+		{"else", &r.Else},
+		{"switch", &r.Switch},
+		{"select", &r.Select},
+		{"selectcase", &r.SelectCase},
+		{"for", &r.For},
+		{"goto", &r.Goto},
+		{"branch", &r.Branch},
+		{"fallthrough", &r.Fallthrough},
+		{"logical", &r.LogicalOp},
+		{"recursion", &r.Recursion},
+		{"closure", &r.Closure},
+		{"go", &r.Go},
+		{"defer", &r.Defer},
+		{"nesting", &r.Nesting},
+	}
+}
+
+type ruleField struct {
+	name string
+	val  *int
+}
+
+// String returns the rules in the form accepted by Set.
+func (r *Rules) String() string {
+	var parts []string
+	for _, f := range r.fields() {
+		parts = append(parts, fmt.Sprintf("%s=%d", f.name, *f.val))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Set updates the rules from a comma separated list of name=increment
+// pairs, e.g. "else=0,closure=0". It implements the flag.Value interface.
+// The rules are left unchanged when any of the pairs is invalid.
+func (r *Rules) Set(s string) error {
+	updated := *r
+	fields := updated.fields()
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, val, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid rule %q, expecting name=increment", pair)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(val))
+		if err != nil {
+			return fmt.Errorf("invalid increment of rule %q: %w", name, err)
+		}
+
+		if n < 0 {
+			return fmt.Errorf("invalid increment of rule %q: negative value", name)
+		}
+
+		if err := setRuleField(fields, strings.TrimSpace(name), n); err != nil {
+			return err
+		}
+	}
+
+	*r = updated
+	return nil
+}
+
+func setRuleField(fields []ruleField, name string, n int) error {
+	for _, f := range fields {
+		if f.name == name {
+			*f.val = n
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown rule %q", name)
+}
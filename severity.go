@@ -0,0 +1,47 @@
+package gocognit
+
+// Severity is the level of a function exceeding a limit, the empty
+// severity means no limit is exceeded.
+type Severity string
+
+// The severities, they are also the categories of the Analyzer
+// diagnostics.
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Levels are the limits of the cognitive complexity, a function with
+// complexity > Warn is a warning and > Error is an error. 0 disables a
+// level.
+type Levels struct {
+	Warn  int
+	Error int
+}
+
+// Enabled reports whether any level is set.
+func (l Levels) Enabled() bool {
+	return l.Warn > 0 || l.Error > 0
+}
+
+// Severity returns the severity of the complexity, the error level takes
+// precedence.
+func (l Levels) Severity(complexity int) Severity {
+	switch {
+	case l.Error > 0 && complexity > l.Error:
+		return SeverityError
+	case l.Warn > 0 && complexity > l.Warn:
+		return SeverityWarning
+	}
+
+	return ""
+}
+
+// Limit returns the limit of the severity.
+func (l Levels) Limit(s Severity) int {
+	if s == SeverityError {
+		return l.Error
+	}
+
+	return l.Warn
+}
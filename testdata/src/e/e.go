@@ -0,0 +1,47 @@
+package testdata
+
+import (
+	"fmt"
+	"io"
+)
+
+// Scored with rules "else=0,closure=0,nesting=0,recursion=2"
+
+func IfElse(n int) string { // want "cognitive complexity 2 of func IfElse is high \\(> 0\\)"
+	if n == 100 { // +1
+		return "a hundred"
+	} else if n == 200 { // +1
+		return "two hundred"
+	} else { // +0
+		return "others"
+	}
+} // total complexity = 2
+
+func NoNesting(a bool) { // want "cognitive complexity 3 of func NoNesting is high \\(> 0\\)"
+	if a { // +1
+		for i := 0; i < 10; i++ { // +1 (nesting = 1)
+			n := 0
+			for n < 10 { // +1 (nesting = 2)
+				n++
+			}
+		}
+	}
+} // total complexity = 3
+
+func Closure(a bool) { // want "cognitive complexity 1 of func Closure is high \\(> 0\\)"
+	x := func() { // +0 (nesting level stays 0)
+		if a { // +1
+			fmt.Fprintln(io.Discard, "true")
+		}
+	}
+
+	x()
+} // total complexity = 1
+
+func Fact(n int) int { // want "cognitive complexity 3 of func Fact is high \\(> 0\\)"
+	if n <= 1 { // +1
+		return 1
+	}
+
+	return n * Fact(n-1) // +2
+} // total complexity = 3
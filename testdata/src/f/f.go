@@ -0,0 +1,54 @@
+package testdata
+
+// Scored with rules "fallthrough=1"
+
+func Goto(n int) int { // want "cognitive complexity 2 of func Goto is high \\(> 0\\)"
+	i := 0
+LOOP:
+	if i < n { // +1
+		i++
+		goto LOOP // +1
+	}
+
+	return i
+} // total complexity = 2
+
+func LabeledBreak(m [][]int) int { // want "cognitive complexity 7 of func LabeledBreak is high \\(> 0\\)"
+	var found int
+OUTER:
+	for _, row := range m { // +1
+		for _, v := range row { // +2 (nesting = 1)
+			if v < 0 { // +3 (nesting = 2)
+				break OUTER // +1
+			}
+			found++
+		}
+	}
+
+	return found
+} // total complexity = 7
+
+func UnlabeledBreak(a []int) int { // want "cognitive complexity 3 of func UnlabeledBreak is high \\(> 0\\)"
+	var sum int
+	for _, v := range a { // +1
+		if v < 0 { // +2 (nesting = 1)
+			break
+		}
+		sum += v
+	}
+
+	return sum
+} // total complexity = 3
+
+func Fallthrough(n int) string { // want "cognitive complexity 2 of func Fallthrough is high \\(> 0\\)"
+	var s string
+	switch n { // +1
+	case 0:
+		s += "zero "
+		fallthrough // +1
+	case 1:
+		s += "small"
+	}
+
+	return s
+} // total complexity = 2
@@ -0,0 +1,67 @@
+package testdata
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Scored with -go-idiom flag
+
+func Parse(s string) (int, error) { // want "cognitive complexity 1 of func Parse is high \\(> 0\\)"
+	n, err := strconv.Atoi(s)
+	if err != nil { // +0 (error check)
+		return 0, err
+	}
+
+	if n < 0 { // +1
+		return 0, errors.New("negative")
+	}
+
+	return n, nil
+} // total complexity = 1
+
+func Wrap(s string) (int, error) {
+	if _, err := strconv.Atoi(s); err != nil { // +0 (error check)
+		return 0, fmt.Errorf("parse %q: %w", s, err)
+	}
+
+	return len(s), nil
+} // total complexity = 0
+
+func Typed(s string) error {
+	_, e := strconv.Atoi(s)
+	if e != nil { // +0 (error check, recognized by the type)
+		return e
+	}
+
+	return nil
+} // total complexity = 0
+
+func NotTrivial(s string) (int, error) { // want "cognitive complexity 1 of func NotTrivial is high \\(> 0\\)"
+	n, err := strconv.Atoi(s)
+	if err != nil { // +1
+		fmt.Println("failed")
+		return 0, err
+	}
+
+	return n, nil
+} // total complexity = 1
+
+func NotError(p *int) int { // want "cognitive complexity 1 of func NotError is high \\(> 0\\)"
+	if p != nil { // +1
+		return *p
+	}
+
+	return 0
+} // total complexity = 1
+
+func Nested(ss []string) error { // want "cognitive complexity 1 of func Nested is high \\(> 0\\)"
+	for _, s := range ss { // +1
+		if _, err := strconv.Atoi(s); err != nil { // +0 (error check)
+			return err
+		}
+	}
+
+	return nil
+} // total complexity = 1
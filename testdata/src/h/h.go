@@ -0,0 +1,46 @@
+package testdata
+
+import (
+	"sync"
+	"time"
+)
+
+// Scored with rules "go=1,defer=1,selectcase=1"
+
+func Spawn(items []int, f func(int)) { // want "cognitive complexity 6 of func Spawn is high \\(> 0\\)"
+	var wg sync.WaitGroup
+	for _, item := range items { // +1
+		wg.Add(1)
+		go func(n int) { // +1 "go" (the literal adds a nesting level)
+			defer wg.Done() // +1
+			if n > 0 {      // +3 (nesting = 2)
+				f(n)
+			}
+		}(item)
+	}
+
+	wg.Wait()
+} // total complexity = 6
+
+func Deferred(mu *sync.Mutex, n int) int { // want "cognitive complexity 1 of func Deferred is high \\(> 0\\)"
+	mu.Lock()
+	defer mu.Unlock() // +1
+
+	return n
+} // total complexity = 1
+
+func Receive(ch <-chan int, done <-chan struct{}) int { // want "cognitive complexity 7 of func Receive is high \\(> 0\\)"
+	select { // +1
+	case v := <-ch: // +1
+		if v > 0 { // +2 (nesting = 1)
+			return v
+		}
+	case <-done: // +1
+		return 0
+	case <-time.After(time.Second): // +1
+		return -1
+	default: // +1
+	}
+
+	return 0
+} // total complexity = 7
@@ -0,0 +1,45 @@
+package testdata
+
+// Scored with -cyclo-over 2 flag
+
+func GetWords(number int) string { // want "cyclomatic complexity 4 of func GetWords is high \\(> 2\\)"
+	switch number {
+	case 1: // +1
+		return "one"
+	case 2: // +1
+		return "a couple"
+	case 3: // +1
+		return "a few"
+	default:
+		return "lots"
+	}
+} // Cyclomatic complexity = 4
+
+func SumOfPrimes(max int) int { // want "cyclomatic complexity 4 of func SumOfPrimes is high \\(> 2\\)"
+	var total int
+
+OUT:
+	for i := 1; i < max; i++ { // +1
+		for j := 2; j < i; j++ { // +1
+			if i%j == 0 { // +1
+				continue OUT
+			}
+		}
+		total += i
+	}
+
+	return total
+} // Cyclomatic complexity = 4
+
+func Logical(a, b, c bool) bool { // want "cyclomatic complexity 3 of func Logical is high \\(> 2\\)"
+	return a && b || c // +1, +1
+} // Cyclomatic complexity = 3
+
+func Receive(ch <-chan int) int {
+	select {
+	case v := <-ch: // +1
+		return v
+	default:
+		return 0
+	}
+} // Cyclomatic complexity = 2
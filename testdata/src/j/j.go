@@ -0,0 +1,47 @@
+package testdata
+
+// Checked with -exported-only and -exclude-func "\.String$" flags
+
+type Exported struct{}
+
+func (e Exported) String() string {
+	if e == (Exported{}) { // +1
+		return "zero"
+	}
+
+	return "exported"
+} // total complexity = 1
+
+func (e *Exported) Do(a bool) int { // want "cognitive complexity 1 of func \\(\\*Exported\\)\\.Do is high \\(> 0\\)"
+	if a { // +1
+		return 1
+	}
+
+	return 0
+} // total complexity = 1
+
+type unexported struct{}
+
+func (u unexported) Do(a bool) int {
+	if a { // +1
+		return 1
+	}
+
+	return 0
+} // total complexity = 1
+
+func Public(a bool) int { // want "cognitive complexity 1 of func Public is high \\(> 0\\)"
+	if a { // +1
+		return 1
+	}
+
+	return 0
+} // total complexity = 1
+
+func private(a bool) int {
+	if a { // +1
+		return 1
+	}
+
+	return 0
+} // total complexity = 1
@@ -0,0 +1,38 @@
+package k
+
+// Scored with -warn 2 -error 5 flags
+
+func Simple(ok bool) int {
+	if ok { // +1
+		return 1
+	}
+
+	return 0
+} // total complexity = 1
+
+func Soft(a, b bool) int { // want "cognitive complexity 3 of func Soft is high \\(> 2\\)"
+	if a { // +1
+		if b { // +2 (nesting=1)
+			return 2
+		}
+	}
+
+	return 0
+} // total complexity = 3
+
+func Hard(xs []int) int { // want "cognitive complexity 7 of func Hard is high \\(> 5\\)"
+	total := 0
+	for _, x := range xs { // +1
+		if x > 0 { // +2 (nesting=1)
+			total += x
+		} else if x < -10 { // +1
+			total -= x
+		}
+
+		if x == 0 && total > 0 { // +2 (nesting=1), +1 &&
+			break
+		}
+	}
+
+	return total
+} // total complexity = 7
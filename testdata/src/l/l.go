@@ -0,0 +1,17 @@
+package testdata
+
+func Negation(a, b, c bool) bool {
+	return !(a && b) || c // +1 for `||`, +1 for `&&` in the negation
+} // total complexity = 2
+
+func Nested(a, b, c, d bool) bool {
+	return a && (b || c) && d // +1 for `&&` sequence, +1 for `||` in the parentheses
+} // total complexity = 2
+
+func Mixed(a, b, c, d bool) bool {
+	return a && b || c && d // +1 for each `&&` `||` `&&` sequence
+} // total complexity = 3
+
+func Comparison(a, b, c, d bool) bool {
+	return a && (b && c) == d // +1 for `&&`, +1 for `&&` in the parentheses
+} // total complexity = 2